@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// catalog is a JSON message file of one language.
+type catalog struct {
+	path         string
+	data         map[string]interface{}
+	ids          map[string]struct{}
+	untranslated []string
+}
+
+// loadCatalog reads the catalog at path. A missing file yields an empty catalog.
+func loadCatalog(path string) (*catalog, error) {
+	c := &catalog{
+		path: path,
+		data: make(map[string]interface{}),
+		ids:  make(map[string]struct{}),
+	}
+
+	buf, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := i18n.ParseMessageFileBytes(buf, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, message := range file.Messages {
+		c.ids[message.ID] = struct{}{}
+		if i18n.NewMessageTemplate(message) == nil {
+			c.untranslated = append(c.untranslated, message.ID)
+		}
+	}
+	sort.Strings(c.untranslated)
+
+	if len(buf) > 0 {
+		if err = json.Unmarshal(buf, &c.data); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// merge adds an empty message for every key missing from the catalog and
+// returns the keys it added.
+func (c *catalog) merge(keys []string) []string {
+	var added []string
+	for _, key := range keys {
+		if _, ok := c.ids[key]; ok {
+			continue
+		}
+
+		c.data[key] = ""
+		c.ids[key] = struct{}{}
+		added = append(added, key)
+	}
+
+	return added
+}
+
+// unused returns the sorted message IDs of the catalog that are not in keys.
+func (c *catalog) unused(keys []string) []string {
+	used := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		used[key] = struct{}{}
+	}
+
+	var unused []string
+	for id := range c.ids {
+		if _, ok := used[id]; !ok {
+			unused = append(unused, id)
+		}
+	}
+	sort.Strings(unused)
+
+	return unused
+}
+
+// write saves the catalog with sorted keys.
+func (c *catalog) write() error {
+	buf, err := json.MarshalIndent(c.data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.path, append(buf, '\n'), 0o644)
+}
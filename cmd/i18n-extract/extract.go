@@ -0,0 +1,265 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// builtinKeys are the message IDs the toolkit itself translates.
+var builtinKeys = []string{
+	"server.errors.something_is_wrong",
+	"server.errors.validation_failed",
+}
+
+// keyArguments maps the functions and methods that take a message ID to the
+// index of that argument.
+var keyArguments = map[string]int{
+	"Trans":            0,
+	"TransPlural":      0,
+	"TransCtx":         1,
+	"WithMessage":      0,
+	"SetPublicMessage": 0,
+}
+
+// validationTags are the struct tags holding validator rules.
+var validationTags = []string{"validate", "binding"}
+
+// ignoredRules are validator keywords that never produce a validation error.
+var ignoredRules = map[string]struct{}{
+	"":          {},
+	"-":         {},
+	"omitempty": {},
+	"dive":      {},
+	"keys":      {},
+	"endkeys":   {},
+}
+
+// extractKeys scans the Go files under root and returns the sorted message IDs
+// they need: literal keys passed to Trans, TransPlural, TransCtx, WithMessage
+// and NewServiceError(errors.New(...)), plus validation.<rule> and
+// attributes.<Field> for every field with a validate or binding tag. The
+// message of a NewServiceError given a public message with SetPublicMessage is
+// internal and not a key. Keys translated through a chain of literal
+// Namespace calls, e.g. t.Namespace("billing").Trans("total", nil), are
+// prefixed with the namespaces; those of a non-literal namespace are skipped.
+func extractKeys(root string) ([]string, error) {
+	keys := make(map[string]struct{})
+	for _, key := range builtinKeys {
+		keys[key] = struct{}{}
+	}
+
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if filepath.Ext(path) != ".go" || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+
+		internal := make(map[*ast.CallExpr]struct{})
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.CallExpr:
+				markInternal(n, internal)
+				if _, ok := internal[n]; !ok {
+					extractCallKeys(n, keys)
+				}
+			case *ast.Field:
+				extractFieldKeys(n, keys)
+			}
+			return true
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	return sorted, nil
+}
+
+// extractCallKeys adds the literal message ID passed to call, if any.
+func extractCallKeys(call *ast.CallExpr, keys map[string]struct{}) {
+	name := funcName(call.Fun)
+
+	if name == "NewServiceError" && len(call.Args) > 0 {
+		if inner, ok := call.Args[0].(*ast.CallExpr); ok && funcName(inner.Fun) == "New" && len(inner.Args) > 0 {
+			addLiteral(inner.Args[0], keys)
+		}
+		return
+	}
+
+	i, ok := keyArguments[name]
+	if !ok || len(call.Args) <= i {
+		return
+	}
+
+	prefix := ""
+	if selector, ok := call.Fun.(*ast.SelectorExpr); ok {
+		if prefix, ok = namespacePrefix(selector.X); !ok {
+			return
+		}
+	}
+
+	if key, ok := literal(call.Args[i]); ok && key != "" {
+		keys[prefix+key] = struct{}{}
+	}
+}
+
+// namespacePrefix returns the key prefix of the chain of Namespace calls x
+// ends with, e.g. "billing.invoices." for t.Namespace("billing").Namespace("invoices").
+// It reports false when a namespace is not a string literal.
+func namespacePrefix(x ast.Expr) (string, bool) {
+	prefix := ""
+	for {
+		call, ok := x.(*ast.CallExpr)
+		if !ok || funcName(call.Fun) != "Namespace" || len(call.Args) != 1 {
+			return prefix, true
+		}
+
+		ns, ok := literal(call.Args[0])
+		if !ok {
+			return "", false
+		}
+		if ns != "" {
+			prefix = ns + "." + prefix
+		}
+
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return prefix, true
+		}
+		x = selector.X
+	}
+}
+
+// markInternal adds to internal the NewServiceError call a SetPublicMessage
+// call is chained on, e.g. NewServiceError(err).SetCode(code).SetPublicMessage(key).
+func markInternal(call *ast.CallExpr, internal map[*ast.CallExpr]struct{}) {
+	if funcName(call.Fun) != "SetPublicMessage" {
+		return
+	}
+
+	for {
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return
+		}
+		if call, ok = selector.X.(*ast.CallExpr); !ok {
+			return
+		}
+		if funcName(call.Fun) == "NewServiceError" {
+			internal[call] = struct{}{}
+			return
+		}
+	}
+}
+
+// extractFieldKeys adds the validation and attribute keys of a tagged struct field.
+func extractFieldKeys(field *ast.Field, keys map[string]struct{}) {
+	if field.Tag == nil || len(field.Names) == 0 {
+		return
+	}
+
+	raw, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return
+	}
+
+	tag := reflect.StructTag(raw)
+	for _, name := range validationTags {
+		rules, ok := tag.Lookup(name)
+		if !ok {
+			continue
+		}
+
+		found := false
+		for _, rule := range strings.Split(rules, ",") {
+			rule = validationRule(rule)
+			if _, ignored := ignoredRules[rule]; ignored {
+				continue
+			}
+			keys["validation."+rule] = struct{}{}
+			found = true
+		}
+
+		if found {
+			for _, ident := range field.Names {
+				keys["attributes."+ident.Name] = struct{}{}
+			}
+		}
+	}
+}
+
+// validationRule returns the tag the validator reports for rule: the rule
+// without its param, e.g. min for min=3, or the whole rule when it is an OR of
+// rules, e.g. email|url or min=3|max=5, which keep their params.
+func validationRule(rule string) string {
+	if strings.Contains(rule, "|") {
+		return rule
+	}
+
+	rule, _, _ = strings.Cut(rule, "=")
+
+	return rule
+}
+
+// funcName returns the name of the called function or method.
+func funcName(fun ast.Expr) string {
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f.Name
+	case *ast.SelectorExpr:
+		return f.Sel.Name
+	}
+
+	return ""
+}
+
+// addLiteral adds expr to keys when it is a non-empty string literal.
+func addLiteral(expr ast.Expr, keys map[string]struct{}) {
+	if key, ok := literal(expr); ok && key != "" {
+		keys[key] = struct{}{}
+	}
+}
+
+// literal returns the value of expr when it is a string literal.
+func literal(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+
+	value, err := strconv.Unquote(lit.Value)
+
+	return value, err == nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+const sourceStub = `package handler
+
+import (
+	"errors"
+
+	"github.com/ghaninia/gokit/response"
+	"github.com/ghaninia/gokit/translation"
+)
+
+var errNotFound = response.NewServiceError(errors.New("users.errors.not_found"))
+
+var errEmailTaken = response.NewServiceError(errors.New("duplicate key")).SetPublicMessage("users.errors.email_taken")
+
+type createUser struct {
+	Name  string ` + "`json:\"name\" binding:\"required,min=3\"`" + `
+	Email string ` + "`validate:\"omitempty,email|url\"`" + `
+	Age   int
+}
+
+func handle(t translation.Translation, key string) {
+	response.NewResponse(t).WithMessage("users.created")
+	t.Trans("users.greeting", nil)
+	t.TransPlural("users.count", 2, nil)
+	t.TransCtx(nil, "users.email.subject", nil)
+	t.Trans(key, nil)
+	t.Namespace("billing").Trans("total", nil)
+	t.Namespace("billing").Namespace("invoices").TransPlural("title", 1, nil)
+	t.Namespace(key).Trans("skipped", nil)
+}
+`
+
+func TestExtractKeys(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "handler.go"), []byte(sourceStub), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "handler_test.go"), []byte(`package handler; func x() { t.Trans("ignored", nil) }`), 0o644))
+
+	keys, err := extractKeys(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"attributes.Email",
+		"attributes.Name",
+		"billing.invoices.title",
+		"billing.total",
+		"server.errors.something_is_wrong",
+		"server.errors.validation_failed",
+		"users.count",
+		"users.created",
+		"users.email.subject",
+		"users.errors.email_taken",
+		"users.errors.not_found",
+		"users.greeting",
+		"validation.email|url",
+		"validation.min",
+		"validation.required",
+	}, keys)
+}
+
+func TestRun(t *testing.T) {
+	src := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "handler.go"), []byte(sourceStub), 0o644))
+
+	locales := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(locales, "fa.json"), []byte(`{
+		"users": {"created": "کاربر ساخته شد"},
+		"users.greeting": "",
+		"legacy.key": "قدیمی"
+	}`), 0o644))
+
+	var out bytes.Buffer
+	missing, err := run(&out, src, locales, "", true)
+	assert.NoError(t, err)
+	assert.True(t, missing)
+	assert.Contains(t, out.String(), "fa.json: 13 added, 1 untranslated, 1 unused")
+	assert.Contains(t, out.String(), "  + validation.required\n")
+	assert.Contains(t, out.String(), "  + validation.email|url\n")
+	assert.Contains(t, out.String(), "  ? users.greeting (untranslated)\n")
+	assert.Contains(t, out.String(), "  - legacy.key (unused)\n")
+
+	var data map[string]interface{}
+	buf, err := os.ReadFile(filepath.Join(locales, "fa.json"))
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(buf, &data))
+	assert.Equal(t, map[string]interface{}{"created": "کاربر ساخته شد"}, data["users"])
+	assert.Equal(t, "", data["validation.required"])
+	assert.Equal(t, "قدیمی", data["legacy.key"])
+	assert.NotContains(t, data, "users.created")
+}
+
+func TestCatalogLanguages(t *testing.T) {
+	languages, err := catalogLanguages(t.TempDir(), " fa, en,,")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fa", "en"}, languages)
+
+	_, err = catalogLanguages(t.TempDir(), " , ")
+	assert.Error(t, err)
+}
+
+func TestValidationRule(t *testing.T) {
+	type input struct {
+		Single string `validate:"min=3"`
+		Or     string `validate:"email|url"`
+		OrArgs string `validate:"len=4|len=6"`
+	}
+
+	// The keys match the tags reported by the validator.
+	err := validator.New().Struct(input{Single: "a", Or: "a", OrArgs: "a"})
+	var errs validator.ValidationErrors
+	assert.ErrorAs(t, err, &errs)
+	assert.Equal(t, errs[0].Tag(), validationRule("min=3"))
+	assert.Equal(t, errs[1].Tag(), validationRule("email|url"))
+	assert.Equal(t, errs[2].Tag(), validationRule("len=4|len=6"))
+}
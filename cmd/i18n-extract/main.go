@@ -0,0 +1,126 @@
+// Command i18n-extract scans Go source for the message IDs passed to the
+// translation and response packages and merges them into per-locale JSON
+// catalogs, flagging the keys no longer used.
+//
+// Usage:
+//
+//	i18n-extract -src . -locales locales -langs fa,en -write
+//
+// Without -write the catalogs are left untouched and only the report is
+// printed. New keys are added with an empty message for translators to fill
+// in. With -check the command exits with status 1 when a catalog lacks a key
+// or still has an empty message, which is useful in CI.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	src := flag.String("src", ".", "directory of the Go source to scan")
+	locales := flag.String("locales", "locales", "directory of the JSON catalogs")
+	langs := flag.String("langs", "", "comma separated languages, defaults to the catalogs found in -locales")
+	write := flag.Bool("write", false, "write the merged catalogs")
+	check := flag.Bool("check", false, "exit with status 1 when a catalog lacks a key or has an empty message")
+	flag.Parse()
+
+	missing, err := run(os.Stdout, *src, *locales, *langs, *write)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "i18n-extract:", err)
+		os.Exit(2)
+	}
+
+	if *check && missing {
+		os.Exit(1)
+	}
+}
+
+// run extracts the keys, merges them into the catalog of every language and
+// prints a report to w. It reports whether any catalog lacked a key or had an
+// empty message.
+func run(w io.Writer, src, locales, langs string, write bool) (bool, error) {
+	keys, err := extractKeys(src)
+	if err != nil {
+		return false, err
+	}
+
+	languages, err := catalogLanguages(locales, langs)
+	if err != nil {
+		return false, err
+	}
+
+	if write {
+		if err = os.MkdirAll(locales, os.ModePerm); err != nil {
+			return false, err
+		}
+	}
+
+	missing := false
+	for _, lang := range languages {
+		c, err := loadCatalog(filepath.Join(locales, lang+".json"))
+		if err != nil {
+			return false, err
+		}
+
+		added := c.merge(keys)
+		unused := c.unused(keys)
+		missing = missing || len(added) > 0 || len(c.untranslated) > 0
+
+		fmt.Fprintf(w, "%s: %d added, %d untranslated, %d unused\n", c.path, len(added), len(c.untranslated), len(unused))
+		for _, key := range added {
+			fmt.Fprintf(w, "  + %s\n", key)
+		}
+		for _, key := range c.untranslated {
+			fmt.Fprintf(w, "  ? %s (untranslated)\n", key)
+		}
+		for _, key := range unused {
+			fmt.Fprintf(w, "  - %s (unused)\n", key)
+		}
+
+		if write && len(added) > 0 {
+			if err = c.write(); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return missing, nil
+}
+
+// catalogLanguages returns the languages listed in langs, or those of the JSON
+// catalogs found in the locales directory.
+func catalogLanguages(locales, langs string) ([]string, error) {
+	if langs != "" {
+		var languages []string
+		for _, lang := range strings.Split(langs, ",") {
+			if lang = strings.TrimSpace(lang); lang != "" {
+				languages = append(languages, lang)
+			}
+		}
+		if len(languages) == 0 {
+			return nil, fmt.Errorf("no language in -langs %q", langs)
+		}
+		return languages, nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(locales, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	languages := make([]string, 0, len(matches))
+	for _, match := range matches {
+		languages = append(languages, strings.TrimSuffix(filepath.Base(match), ".json"))
+	}
+
+	if len(languages) == 0 {
+		return nil, fmt.Errorf("no catalog found in %s, use -langs", locales)
+	}
+
+	return languages, nil
+}
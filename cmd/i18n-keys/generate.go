@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"text/template/parse"
+	"unicode"
+
+	"github.com/ghaninia/gokit/translation"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// reservedParams are the parameter names a generated function already uses.
+var reservedParams = map[string]struct{}{
+	"t":         {},
+	"languages": {},
+	"count":     {},
+}
+
+// argumentTypes are the parameter types of the arguments of ICU choices, by
+// kind. The other arguments are interface{}.
+var argumentTypes = map[string]string{
+	"select":        "string",
+	"plural":        "int",
+	"selectordinal": "int",
+}
+
+// message is a message ID and what its generated function needs.
+type message struct {
+	id     string
+	name   string
+	params []*param
+	plural bool
+}
+
+// param is an argument of a message and its parameter type, empty while unknown.
+type param struct {
+	field string
+	typ   string
+}
+
+// placeholder is an argument used by a message source, at its offset.
+type placeholder struct {
+	field  string
+	typ    string
+	offset int
+}
+
+// collectMessages merges the messages of every language loaded in t.
+// Template parameters are the union of those used in every language and plural
+// form, including the arguments of ICU choices, in order of first appearance.
+// The arguments of ICU choices are typed by the kind of the choice; an
+// argument typed differently by two choices is interface{}.
+func collectMessages(t translation.Translation, languages []string) ([]*message, error) {
+	byID := make(map[string]*message)
+	params := make(map[string]map[string]*param)
+
+	for _, lang := range languages {
+		for _, m := range t.Messages(lang) {
+			msg, ok := byID[m.ID]
+			if !ok {
+				msg = &message{id: m.ID, name: identifier(m.ID)}
+				byID[m.ID] = msg
+				params[m.ID] = make(map[string]*param)
+			}
+
+			if m.Zero != "" || m.One != "" || m.Two != "" || m.Few != "" || m.Many != "" {
+				msg.plural = true
+			}
+
+			for _, src := range []string{m.Other, m.Zero, m.One, m.Two, m.Few, m.Many} {
+				placeholders, err := messagePlaceholders(m, src)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %s: %w", lang, m.ID, err)
+				}
+				for _, ph := range placeholders {
+					p, ok := params[m.ID][ph.field]
+					if !ok {
+						p = &param{field: ph.field, typ: ph.typ}
+						params[m.ID][ph.field] = p
+						msg.params = append(msg.params, p)
+						continue
+					}
+					if p.typ == "" {
+						p.typ = ph.typ
+					} else if ph.typ != "" && ph.typ != p.typ {
+						p.typ = "interface{}"
+					}
+				}
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	messages := make([]*message, 0, len(byID))
+	names := make(map[string]string, 2*len(byID))
+	for _, id := range ids {
+		msg := byID[id]
+		for _, name := range []string{msg.name, "Key" + msg.name} {
+			if other, ok := names[name]; ok {
+				return nil, fmt.Errorf("message IDs %q and %q both generate %s", other, id, name)
+			}
+			names[name] = id
+		}
+
+		if msg.plural {
+			delete(params[id], translation.CountKey)
+		}
+		kept := msg.params[:0]
+		fields := make(map[string]string, len(msg.params))
+		for _, p := range msg.params {
+			if _, ok := params[id][p.field]; !ok {
+				continue
+			}
+			name := paramName(p.field)
+			if other, ok := fields[name]; ok {
+				return nil, fmt.Errorf("message ID %q: arguments %q and %q both generate the parameter %s", id, other, p.field, name)
+			}
+			fields[name] = p.field
+			if p.typ == "" {
+				p.typ = "interface{}"
+			}
+			kept = append(kept, p)
+		}
+		msg.params = kept
+
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+// messagePlaceholders returns the template fields and the ICU arguments of a
+// message source in order of appearance.
+func messagePlaceholders(m *i18n.Message, src string) ([]placeholder, error) {
+	placeholders, err := templateFields(m, src)
+	if err != nil {
+		return nil, err
+	}
+
+	arguments, err := translation.MessageArguments(src)
+	if err != nil {
+		return nil, err
+	}
+	for _, argument := range arguments {
+		placeholders = append(placeholders, placeholder{
+			field:  argument.Name,
+			typ:    argumentTypes[argument.Kind],
+			offset: argument.Offset,
+		})
+	}
+
+	sort.SliceStable(placeholders, func(i, j int) bool {
+		return placeholders[i].offset < placeholders[j].offset
+	})
+
+	return placeholders, nil
+}
+
+// templateFields returns the top-level fields, such as {{.name}}, used by a
+// message template, with their offsets.
+func templateFields(m *i18n.Message, src string) ([]placeholder, error) {
+	if src == "" {
+		return nil, nil
+	}
+
+	left, right := m.LeftDelim, m.RightDelim
+	if left == "" {
+		left = "{{"
+	}
+	if right == "" {
+		right = "}}"
+	}
+	if !strings.Contains(src, left) {
+		return nil, nil
+	}
+
+	tree := parse.New(m.ID)
+	tree.Mode = parse.SkipFuncCheck
+	if _, err := tree.Parse(src, left, right, map[string]*parse.Tree{}); err != nil {
+		return nil, err
+	}
+
+	var fields []placeholder
+	var walk func(node parse.Node)
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		case *parse.FieldNode:
+			fields = append(fields, placeholder{field: n.Ident[0], offset: int(n.Pos)})
+		case *parse.IfNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			// The dot changes inside range and with, only their pipeline is top-level.
+			walk(n.Pipe)
+		case *parse.WithNode:
+			walk(n.Pipe)
+		}
+	}
+	walk(tree.Root)
+
+	return fields, nil
+}
+
+// identifier converts a message ID such as server.errors.something_is_wrong
+// to an exported Go identifier such as ServerErrorsSomethingIsWrong.
+func identifier(id string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(id, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(part)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+
+	name := b.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "Msg" + name
+	}
+
+	return name
+}
+
+// paramName converts a template field to a Go parameter name.
+func paramName(field string) string {
+	name := []rune(identifier(field))
+	name[0] = unicode.ToLower(name[0])
+
+	param := string(name)
+	if _, ok := reservedParams[param]; ok || token.IsKeyword(param) {
+		param += "Arg"
+	}
+
+	return param
+}
+
+// generate renders the Go source declaring a constant and a function per message.
+func generate(pkg string, messages []*message) ([]byte, error) {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "// Code generated by i18n-keys. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "import \"github.com/ghaninia/gokit/translation\"\n\n")
+
+	fmt.Fprintf(&b, "// Message IDs of the loaded catalogs.\nconst (\n")
+	for _, m := range messages {
+		fmt.Fprintf(&b, "\tKey%s = %q\n", m.name, m.id)
+	}
+	fmt.Fprintf(&b, ")\n")
+
+	for _, m := range messages {
+		var params, args []string
+		for _, p := range m.params {
+			params = append(params, paramName(p.field)+" "+p.typ)
+			args = append(args, fmt.Sprintf("%q: %s", p.field, paramName(p.field)))
+		}
+
+		data := "nil"
+		if len(args) > 0 {
+			data = "map[string]interface{}{" + strings.Join(args, ", ") + "}"
+		}
+
+		fmt.Fprintf(&b, "\n// %s translates the message %q.\n", m.name, m.id)
+		if m.plural {
+			fmt.Fprintf(&b, "func %s(t translation.Translation, count int, %s languages ...string) string {\n", m.name, joinParams(params))
+			fmt.Fprintf(&b, "\treturn t.TransPlural(Key%s, count, %s, languages...)\n}\n", m.name, data)
+			continue
+		}
+		fmt.Fprintf(&b, "func %s(t translation.Translation, %s languages ...string) string {\n", m.name, joinParams(params))
+		fmt.Fprintf(&b, "\treturn t.Trans(Key%s, %s, languages...)\n}\n", m.name, data)
+	}
+
+	return format.Source(b.Bytes())
+}
+
+// joinParams joins parameters so that they can be followed by another one.
+func joinParams(params []string) string {
+	if len(params) == 0 {
+		return ""
+	}
+
+	return strings.Join(params, ", ") + ","
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"en.json": `{
+			"server.errors.something_is_wrong": "Something is wrong",
+			"validation.required": "{{.attribute}} is required",
+			"users.count": {"one": "{{.count}} user", "other": "{{.count}} users in {{.group}}"},
+			"users.welcome": "{{if .admin}}Welcome back{{end}} {{.name | printf \"%s\"}}",
+			"users.type": "{{.type}}",
+			"users.liked": "{gender, select, female {She} other {They}} liked {{.post}}",
+			"users.transfer": "{{.amount}} to {{.to}} from {{.from}}, {rank, selectordinal, other {#th}}"
+		}`,
+		"fa.json": `{"validation.required": "{{.attribute}} الزامی است", "users.only_fa": "{{.title}}"}`,
+	}
+	for name, content := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	src, err := run(dir, "locales", false)
+	assert.NoError(t, err)
+
+	out := string(src)
+	assert.Contains(t, out, "// Code generated by i18n-keys. DO NOT EDIT.\n\npackage locales\n")
+	assert.Contains(t, out, `KeyServerErrorsSomethingIsWrong = "server.errors.something_is_wrong"`)
+	assert.Contains(t, out, "func ServerErrorsSomethingIsWrong(t translation.Translation, languages ...string) string {\n\treturn t.Trans(KeyServerErrorsSomethingIsWrong, nil, languages...)\n}")
+	assert.Contains(t, out, "func ValidationRequired(t translation.Translation, attribute interface{}, languages ...string) string {\n\treturn t.Trans(KeyValidationRequired, map[string]interface{}{\"attribute\": attribute}, languages...)\n}")
+	assert.Contains(t, out, "func UsersCount(t translation.Translation, count int, group interface{}, languages ...string) string {\n\treturn t.TransPlural(KeyUsersCount, count, map[string]interface{}{\"group\": group}, languages...)\n}")
+	assert.Contains(t, out, "func UsersWelcome(t translation.Translation, admin interface{}, name interface{}, languages ...string) string {")
+	assert.Contains(t, out, "func UsersType(t translation.Translation, typeArg interface{}, languages ...string) string {")
+	assert.Contains(t, out, "func UsersLiked(t translation.Translation, gender string, post interface{}, languages ...string) string {")
+	assert.Contains(t, out, "func UsersTransfer(t translation.Translation, amount interface{}, to interface{}, from interface{}, rank int, languages ...string) string {")
+	assert.Contains(t, out, "func UsersOnlyFa(t translation.Translation, title interface{}, languages ...string) string {")
+}
+
+func TestRun_Namespaces(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "billing"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "en.json"), []byte(`{"greeting": "Hello"}`), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "billing", "en.json"), []byte(`{"total": "Total {{.amount}}"}`), 0o644))
+
+	src, err := run(dir, "locales", true)
+	assert.NoError(t, err)
+
+	out := string(src)
+	assert.Contains(t, out, "func Greeting(t translation.Translation, languages ...string) string {")
+	assert.Contains(t, out, `KeyBillingTotal = "billing.total"`)
+	assert.Contains(t, out, "func BillingTotal(t translation.Translation, amount interface{}, languages ...string) string {")
+	assert.NotContains(t, out, "KeyTotal")
+}
+
+func TestCollectMessages_Collision(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "en.json"), []byte(`{"users.name": "a", "users_name": "b"}`), 0o644))
+
+	_, err := run(dir, "locales", false)
+	assert.ErrorContains(t, err, "both generate UsersName")
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "en.json"), []byte(`{"users": "a", "key.users": "b"}`), 0o644))
+	_, err = run(dir, "locales", false)
+	assert.ErrorContains(t, err, `message IDs "key.users" and "users" both generate KeyUsers`)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "en.json"), []byte(`{"users.show": "{{.user_id}} {{.userId}}"}`), 0o644))
+	_, err = run(dir, "locales", false)
+	assert.ErrorContains(t, err, `message ID "users.show": arguments "user_id" and "userId" both generate the parameter userId`)
+}
+
+func TestIdentifier(t *testing.T) {
+	assert.Equal(t, "ServerErrorsSomethingIsWrong", identifier("server.errors.something_is_wrong"))
+	assert.Equal(t, "Msg404NotFound", identifier("404.not-found"))
+	assert.Equal(t, "rangeArg", paramName("range"))
+	assert.Equal(t, "firstName", paramName("first_name"))
+}
@@ -0,0 +1,79 @@
+// Command i18n-keys reads the locale catalogs and generates a Go file with a
+// constant per message ID and a function per message taking its template
+// arguments as parameters, so that a mistyped key or a missing argument is a
+// compile error.
+//
+// Usage:
+//
+//	i18n-keys -locales locales -pkg locales -out locales/keys.go
+//
+// Messages with plural forms get a count int parameter and are translated with
+// TransPlural. The parameters follow the order in which the arguments first
+// appear in the messages. The argument of an ICU select is a string and that
+// of an ICU plural or selectordinal an int; template arguments such as
+// {{.name}} are interface{}.
+//
+// With -namespaces the catalogs in subdirectories are loaded as namespaces, as
+// with translation.Config.Namespaces, so billing/en.json {"total": "Total"}
+// generates KeyBillingTotal = "billing.total".
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ghaninia/gokit/translation"
+)
+
+func main() {
+	locales := flag.String("locales", "locales", "directory of the catalogs")
+	pkg := flag.String("pkg", "locales", "package name of the generated file")
+	out := flag.String("out", "", "generated file, defaults to stdout")
+	namespaces := flag.Bool("namespaces", false, "load the catalogs of subdirectories as namespaces")
+	flag.Parse()
+
+	src, err := run(*locales, *pkg, *namespaces)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "i18n-keys:", err)
+		os.Exit(1)
+	}
+
+	if *out == "" {
+		_, _ = os.Stdout.Write(src)
+		return
+	}
+
+	if err = os.WriteFile(*out, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "i18n-keys:", err)
+		os.Exit(1)
+	}
+}
+
+// run loads the catalogs of the locales directory, with their subdirectories
+// as namespaces when namespaces is set, and generates the Go source.
+func run(locales, pkg string, namespaces bool) ([]byte, error) {
+	t := translation.NewTranslation(translation.Config{
+		PathLocale: locales,
+		Namespaces: namespaces,
+		Logger:     translation.NopLogger,
+	})
+	if err := t.Reload(); err != nil {
+		return nil, err
+	}
+
+	var languages []string
+	for _, tag := range t.Languages() {
+		languages = append(languages, tag.String())
+	}
+	if len(languages) == 0 {
+		return nil, fmt.Errorf("no catalog found in %s", locales)
+	}
+
+	messages, err := collectMessages(t, languages)
+	if err != nil {
+		return nil, err
+	}
+
+	return generate(pkg, messages)
+}
@@ -1,6 +1,7 @@
 package meta
 
 import (
+	"encoding/json"
 	"github.com/gin-gonic/gin"
 	"reflect"
 )
@@ -68,6 +69,23 @@ func (c collection[T]) GetMeta(ctx *gin.Context) Meta {
 
 type Meta struct {
 	Pagination Pagination `json:"pagination"`
+	Locale     *Locale    `json:"locale,omitempty"`
+}
+
+// MarshalJSON omits the pagination when it is the zero value, as for the meta
+// WithLocaleMeta adds to a response that is not paginated.
+func (m Meta) MarshalJSON() ([]byte, error) {
+	type meta struct {
+		Pagination *Pagination `json:"pagination,omitempty"`
+		Locale     *Locale     `json:"locale,omitempty"`
+	}
+
+	res := meta{Locale: m.Locale}
+	if m.Pagination != (Pagination{}) {
+		res.Pagination = &m.Pagination
+	}
+
+	return json.Marshal(res)
 }
 
 type Pagination struct {
@@ -76,3 +94,10 @@ type Pagination struct {
 	PageCount  int `json:"pageCount"`
 	TotalCount int `json:"totalCount"`
 }
+
+// Locale describes the locale a response is written in.
+type Locale struct {
+	Code      string `json:"code"`
+	Direction string `json:"direction"`
+	Name      string `json:"name"`
+}
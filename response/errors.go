@@ -1,20 +1,60 @@
 package response
 
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// maxStackDepth is the number of frames captured by a ServiceError.
+const maxStackDepth = 32
+
 type Error interface {
 	Error() string
 	GetType() string
 	GetAttributes() map[string]interface{}
 }
 
+// Coder is implemented by errors carrying a stable code, such as
+// users.not_found, which clients and the StatusRegistry can rely on.
+type Coder interface {
+	GetCode() string
+}
+
+// PublicMessager is implemented by errors whose message shown to clients
+// differs from their internal Error text.
+type PublicMessager interface {
+	GetPublicMessage() string
+}
+
+// StatusCoder is implemented by errors carrying their own HTTP status.
+type StatusCoder interface {
+	GetStatus() int
+}
+
 type ServiceError struct {
 	err        error
 	errorType  string
+	code       string
+	status     int
+	public     string
 	attributes map[string]interface{}
+	stack      []uintptr
 }
 
+// NewServiceError creates an error wrapping err and captures the stack trace.
+// The message of err is the public message unless SetPublicMessage is used.
 func NewServiceError(
 	err error,
 	attrs ...map[string]interface{},
+) *ServiceError {
+	return newServiceError(err, attrs...)
+}
+
+func newServiceError(
+	err error,
+	attrs ...map[string]interface{},
 ) *ServiceError {
 	attributes := make(map[string]interface{})
 
@@ -22,29 +62,184 @@ func NewServiceError(
 		attributes = attrs[0]
 	}
 
+	stack := make([]uintptr, maxStackDepth)
+	// Skip runtime.Callers, newServiceError and its exported caller.
+	stack = stack[:runtime.Callers(3, stack)]
+
 	return &ServiceError{
 		err:        err,
 		attributes: attributes,
+		stack:      stack,
 	}
 }
 
-func (e *ServiceError) SetType(errorType string) Error {
+// Wrap wraps err in a ServiceError of the error type, adding context while
+// keeping err visible to errors.Is and errors.As. The type, code, status and
+// attributes of a ServiceError wrapped by err are inherited; the attributes
+// given here are merged over them.
+func Wrap(
+	err error,
+	errorType string,
+	attrs ...map[string]interface{},
+) *ServiceError {
+	return newServiceError(err, attrs...).SetType(errorType)
+}
+
+func (e *ServiceError) SetType(errorType string) *ServiceError {
 	e.errorType = errorType
 	return e
 }
 
+// SetCode sets the stable code of the error.
+func (e *ServiceError) SetCode(code string) *ServiceError {
+	e.code = code
+	return e
+}
+
+// SetStatus sets the HTTP status of the error, which takes precedence over
+// the StatusRegistry.
+func (e *ServiceError) SetStatus(status int) *ServiceError {
+	e.status = status
+	return e
+}
+
+// SetPublicMessage sets the message shown to clients, usually a translation
+// key, keeping the wrapped error as internal details.
+func (e *ServiceError) SetPublicMessage(message string) *ServiceError {
+	e.public = message
+	return e
+}
+
 func (e *ServiceError) GetMessage() string {
-	return e.err.Error()
+	return e.Error()
 }
 
+// GetPublicMessage returns the message shown to clients: the one set with
+// SetPublicMessage, or that of the ServiceError it wraps, or else its Error text.
+func (e *ServiceError) GetPublicMessage() string {
+	if message := e.publicMessage(); message != "" {
+		return message
+	}
+
+	return e.Error()
+}
+
+// publicMessage returns the message set with SetPublicMessage on the error or
+// on the ServiceError it wraps, empty when none is set.
+func (e *ServiceError) publicMessage() string {
+	if e.public == "" {
+		if inner := e.inner(); inner != nil {
+			return inner.publicMessage()
+		}
+	}
+
+	return e.public
+}
+
+// StackTrace returns the stack captured when the error was created, one
+// function per line followed by its file and line.
+func (e *ServiceError) StackTrace() string {
+	var b strings.Builder
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" {
+			fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		}
+		if !more {
+			break
+		}
+	}
+
+	return b.String()
+}
+
+// GetType returns the type of the error, or that of the ServiceError it wraps.
 func (e *ServiceError) GetType() string {
+	if e.errorType == "" {
+		if inner := e.inner(); inner != nil {
+			return inner.GetType()
+		}
+	}
+
 	return e.errorType
 }
 
+// GetCode returns the code of the error, or that of the ServiceError it wraps.
+func (e *ServiceError) GetCode() string {
+	if e.code == "" {
+		if inner := e.inner(); inner != nil {
+			return inner.GetCode()
+		}
+	}
+
+	return e.code
+}
+
+// GetStatus returns the status of the error, or that of the ServiceError it wraps.
+func (e *ServiceError) GetStatus() int {
+	if e.status == 0 {
+		if inner := e.inner(); inner != nil {
+			return inner.GetStatus()
+		}
+	}
+
+	return e.status
+}
+
+// GetAttributes returns the attributes of the error merged over those of the
+// ServiceError it wraps.
 func (e *ServiceError) GetAttributes() map[string]interface{} {
-	return e.attributes
+	inner := e.inner()
+	if inner == nil {
+		return e.attributes
+	}
+
+	attributes := make(map[string]interface{})
+	for k, v := range inner.GetAttributes() {
+		attributes[k] = v
+	}
+	for k, v := range e.attributes {
+		attributes[k] = v
+	}
+
+	return attributes
 }
 
 func (e *ServiceError) Error() string {
+	if e.err == nil {
+		return ""
+	}
+
 	return e.err.Error()
 }
+
+// Unwrap returns the wrapped error.
+func (e *ServiceError) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether target is a ServiceError with the same code, or the same
+// type when target has no code, so that ServiceErrors can be sentinels.
+func (e *ServiceError) Is(target error) bool {
+	t, ok := target.(*ServiceError)
+	if !ok {
+		return false
+	}
+
+	if t.code != "" {
+		return t.code == e.GetCode()
+	}
+
+	return t.errorType != "" && t.errorType == e.GetType()
+}
+
+// inner returns the closest ServiceError wrapped by e, or nil.
+func (e *ServiceError) inner() *ServiceError {
+	var inner *ServiceError
+	if e.err == nil || !errors.As(e.err, &inner) {
+		return nil
+	}
+
+	return inner
+}
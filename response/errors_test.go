@@ -2,9 +2,12 @@ package response
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -61,3 +64,196 @@ func TestServiceError_WithResponseError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, resp["errors"].([]ErrorResponse)[0].Status)
 	assert.Equal(t, "test", resp["errors"].([]ErrorResponse)[0].Attributes["test"])
 }
+
+func TestResource_WithMultipleErrors(t *testing.T) {
+	notFound := NewServiceError(errors.New("user not found")).SetType("user_not_found").SetCode("users.not_found")
+	conflict := NewServiceError(errors.New("email taken"), map[string]interface{}{"field": "email"}).SetType("email_taken").SetStatus(http.StatusConflict)
+	mapping := NewStatusRegistry().RegisterCode("users.not_found", http.StatusNotFound)
+
+	statusCode, resp := NewResponse(nil, mapping).
+		WithError(errors.Join(notFound, conflict)).
+		WithError(nil).
+		EchoPure()
+
+	errs := resp["errors"].([]ErrorResponse)
+	assert.Equal(t, http.StatusConflict, statusCode)
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "user_not_found", errs[0].TypeInfo)
+	assert.Equal(t, "users.not_found", errs[0].Code)
+	assert.Equal(t, http.StatusNotFound, errs[0].Status)
+	assert.Equal(t, "email_taken", errs[1].TypeInfo)
+	assert.Equal(t, http.StatusConflict, errs[1].Status)
+	assert.Equal(t, "email", errs[1].Attributes["field"])
+
+	statusCode, resp = NewResponse(nil, mapping).WithError(notFound).WithError(errStub).EchoPure()
+	assert.Equal(t, http.StatusInternalServerError, statusCode)
+	assert.Len(t, resp["errors"], 2)
+
+	statusCode, resp = NewResponse(nil, mapping).WithError(notFound).WithError(errStub).WithStatusCode(http.StatusBadRequest).EchoPure()
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, http.StatusBadRequest, resp["errors"].([]ErrorResponse)[1].Status)
+}
+
+func TestResource_WithErrorsAndValidation(t *testing.T) {
+	trans := newTranslationStub(t)
+	err := validator.New().Struct(struct {
+		Name string `validate:"required"`
+	}{})
+
+	statusCode, resp := NewResponse(trans).Validation(err).EchoPure()
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, Validations{"Name": {"name is required"}}, resp["errors"])
+
+	statusCode, resp = NewResponse(trans).Validation(err).WithError(errStub).EchoPure()
+	errs := resp["errors"].([]ErrorResponse)
+	assert.Equal(t, http.StatusInternalServerError, statusCode)
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "stub", errs[0].TypeInfo)
+	assert.Equal(t, "validation_failed", errs[1].TypeInfo)
+	assert.Equal(t, http.StatusUnprocessableEntity, errs[1].Status)
+	assert.Equal(t, Validations{"Name": {"name is required"}}, errs[1].Attributes["fields"])
+}
+
+func TestResource_ValidationWithOtherError(t *testing.T) {
+	statusCode, resp := NewResponse(nil).Validation(errStub).EchoPure()
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Contains(t, resp, "errors")
+	assert.Nil(t, resp["errors"])
+
+	statusCode, resp = NewResponse(nil).Validation(errStub).WithError(errors.New("conflict")).EchoPure()
+	errs := resp["errors"].([]ErrorResponse)
+	assert.Equal(t, http.StatusInternalServerError, statusCode)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "conflict", errs[0].Detail)
+}
+
+var errUserNotFoundStub = NewServiceError(errors.New("users.errors.not_found")).SetCode("users.not_found").SetType("user_not_found")
+
+func TestWrap(t *testing.T) {
+	inner := NewServiceError(errStub, map[string]interface{}{"id": 1, "table": "users"}).
+		SetType("record_not_found").
+		SetCode("records.not_found").
+		SetStatus(http.StatusNotFound)
+
+	wrapped := Wrap(fmt.Errorf("load profile: %w", inner), "", map[string]interface{}{"table": "profiles", "user": 2})
+
+	assert.Equal(t, "load profile: stub", wrapped.Error())
+	assert.Equal(t, "record_not_found", wrapped.GetType())
+	assert.Equal(t, "records.not_found", wrapped.GetCode())
+	assert.Equal(t, http.StatusNotFound, wrapped.GetStatus())
+	assert.Equal(t, map[string]interface{}{"id": 1, "table": "profiles", "user": 2}, wrapped.GetAttributes())
+	assert.Equal(t, map[string]interface{}{"id": 1, "table": "users"}, inner.GetAttributes())
+
+	outer := Wrap(wrapped, "profile_not_found").SetStatus(http.StatusGone)
+	assert.Equal(t, "profile_not_found", outer.GetType())
+	assert.Equal(t, http.StatusGone, outer.GetStatus())
+	assert.Equal(t, "records.not_found", outer.GetCode())
+
+	assert.True(t, errors.Is(outer, errStub))
+	assert.True(t, errors.Is(outer, inner))
+
+	var target *ServiceError
+	assert.True(t, errors.As(fmt.Errorf("handler: %w", outer), &target))
+	assert.Equal(t, outer, target)
+}
+
+func TestServiceError_Is(t *testing.T) {
+	err := Wrap(NewServiceError(errors.New("not found")).SetCode("users.not_found"), "user_not_found")
+
+	assert.True(t, errors.Is(err, errUserNotFoundStub))
+	assert.True(t, errors.Is(fmt.Errorf("get user: %w", err), errUserNotFoundStub))
+	assert.False(t, errors.Is(NewServiceError(errStub).SetCode("users.conflict"), errUserNotFoundStub))
+	assert.True(t, errors.Is(NewServiceError(errStub).SetType("user_not_found"), NewServiceError(nil).SetType("user_not_found")))
+	assert.False(t, errors.Is(NewServiceError(errStub), NewServiceError(nil)))
+	assert.Equal(t, "", NewServiceError(nil).Error())
+}
+
+func TestResource_WithWrappedError(t *testing.T) {
+	inner := NewServiceError(errors.New("users.errors.not_found"), map[string]interface{}{"id": 7}).SetCode("users.not_found")
+	registry := NewStatusRegistry().RegisterError(errUserNotFoundStub, http.StatusNotFound)
+
+	err := fmt.Errorf("handler: %w", Wrap(inner, "user_not_found", map[string]interface{}{"request": "abc"}))
+	statusCode, resp := NewResponse(nil, registry).WithError(err).EchoPure()
+
+	errs := resp["errors"].([]ErrorResponse)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+	assert.Equal(t, "user_not_found", errs[0].TypeInfo)
+	assert.Equal(t, "users.not_found", errs[0].Code)
+	assert.Equal(t, "users.errors.not_found", errs[0].Detail)
+	assert.Equal(t, map[string]interface{}{"id": 7, "request": "abc"}, errs[0].Attributes)
+}
+
+func TestServiceError_PublicMessage(t *testing.T) {
+	err := NewServiceError(errors.New("pq: duplicate key value violates unique constraint \"users_email_key\"")).
+		SetPublicMessage("users.errors.email_taken").
+		SetType("email_taken")
+
+	assert.Equal(t, "users.errors.email_taken", err.GetPublicMessage())
+	assert.Contains(t, err.Error(), "users_email_key")
+	assert.Equal(t, "users.errors.email_taken", Wrap(fmt.Errorf("create user: %w", err), "").GetPublicMessage())
+	assert.Equal(t, "stub", NewServiceError(errStub).GetPublicMessage())
+
+	_, resp := NewResponse(nil).WithError(err).EchoPure()
+	assert.Equal(t, "users.errors.email_taken", resp["errors"].([]ErrorResponse)[0].Detail)
+}
+
+func TestServiceError_StackTrace(t *testing.T) {
+	stack := NewServiceError(errStub).StackTrace()
+	assert.True(t, strings.HasPrefix(stack, "github.com/ghaninia/gokit/response.TestServiceError_StackTrace\n"))
+	assert.Contains(t, stack, "errors_test.go:")
+
+	stack = Wrap(errStub, "wrapped").StackTrace()
+	assert.True(t, strings.HasPrefix(stack, "github.com/ghaninia/gokit/response.TestServiceError_StackTrace\n"))
+}
+
+func TestResource_WithProductionMode(t *testing.T) {
+	trans := newTranslationStub(t)
+	internal := errors.New("open /etc/app/secrets.json: permission denied")
+	typed := NewServiceError(errors.New("internal")).SetType("typed").SetPublicMessage("greeting")
+
+	var reported []error
+	statusCode, resp := NewResponse(trans).
+		WithError(fmt.Errorf("load config: %w", internal)).
+		WithError(typed).
+		WithProductionMode(func(err error) {
+			reported = append(reported, err)
+		}).
+		EchoPure()
+
+	errs := resp["errors"].([]ErrorResponse)
+	assert.Equal(t, http.StatusInternalServerError, statusCode)
+	assert.Equal(t, "something_is_wrong", errs[0].TypeInfo)
+	assert.Equal(t, "server.errors.something_is_wrong", errs[0].Detail)
+	assert.NotContains(t, errs[0].Detail, "secrets")
+	assert.Equal(t, "typed", errs[1].TypeInfo)
+	assert.Equal(t, "Hello", errs[1].Detail)
+	assert.Len(t, reported, 1)
+	assert.ErrorIs(t, reported[0], internal)
+
+	assert.NotPanics(t, func() {
+		NewResponse(nil).WithError(internal).WithProductionMode(nil).EchoPure()
+	})
+}
+
+func TestResource_WithProductionModeHidesWrappedErrors(t *testing.T) {
+	wrapped := Wrap(errors.New("pq: relation \"users\" does not exist"), "db_error")
+
+	var reported []error
+	_, resp := NewResponse(newTranslationStub(t)).
+		WithError(wrapped).
+		WithError(Wrap(wrapped, "").SetPublicMessage("greeting")).
+		WithProductionMode(func(err error) {
+			reported = append(reported, err)
+		}).
+		EchoPure()
+
+	errs := resp["errors"].([]ErrorResponse)
+	assert.Equal(t, "db_error", errs[0].TypeInfo)
+	assert.Equal(t, "server.errors.something_is_wrong", errs[0].Detail)
+	assert.NotContains(t, errs[0].Detail, "pq:")
+	assert.Equal(t, "Hello", errs[1].Detail)
+	assert.Equal(t, []error{wrapped}, reported)
+
+	_, resp = NewResponse(nil).WithError(wrapped).EchoPure()
+	assert.Contains(t, resp["errors"].([]ErrorResponse)[0].Detail, "pq:")
+}
@@ -0,0 +1,81 @@
+package response
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const problemContentType = "application/problem+json"
+
+// problemMembers are the members defined by RFC 9457, and the code and errors
+// members of the response, which attributes cannot override.
+var problemMembers = map[string]struct{}{
+	"code":     {},
+	"errors":   {},
+	"type":     {},
+	"title":    {},
+	"status":   {},
+	"detail":   {},
+	"instance": {},
+}
+
+// problemDetails is an RFC 9457 problem details object.
+type problemDetails map[string]any
+
+func newProblemDetails(statusCode int) problemDetails {
+	return problemDetails{
+		"type":   "about:blank",
+		"title":  http.StatusText(statusCode),
+		"status": statusCode,
+	}
+}
+
+// setError sets the type and detail of the problem from the error. Its
+// attributes become extension members, except those named like a member of
+// problemMembers, which are skipped.
+func (p problemDetails) setError(errType, code, detail string, attributes map[string]interface{}) {
+	p["type"] = errType
+	if code != "" {
+		p["code"] = code
+	}
+	p["detail"] = detail
+
+	for key, value := range attributes {
+		if _, ok := problemMembers[key]; !ok {
+			p[key] = value
+		}
+	}
+}
+
+// isProblem reports whether the response is rendered as problem details.
+func (r *Resource) isProblem() bool {
+	return r.problem && (len(r.errs) > 0 || errors.As(r.validation, &validator.ValidationErrors{}))
+}
+
+// problemType returns the type URI of an error type, resolved against the
+// base given to WithProblemDetails. Without a base, a type that is not an
+// absolute URI is about:blank, as is an empty type.
+func (r *Resource) problemType(errType string) string {
+	if errType == "" {
+		return "about:blank"
+	}
+
+	ref, err := url.Parse(errType)
+	if err == nil && ref.IsAbs() {
+		return errType
+	}
+
+	if r.problemTypeBase == "" {
+		return "about:blank"
+	}
+
+	base, baseErr := url.Parse(r.problemTypeBase)
+	if err != nil || baseErr != nil {
+		return r.problemTypeBase + errType
+	}
+
+	return base.ResolveReference(ref).String()
+}
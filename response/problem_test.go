@@ -0,0 +1,121 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func echoProblem(t *testing.T, response func() Response) (*httptest.ResponseRecorder, map[string]interface{}) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/users/:id", func(ctx *gin.Context) {
+		response().Echo(ctx)
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/42?expand=roles", nil))
+
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+
+	return rec, body
+}
+
+func TestResource_WithProblemDetails(t *testing.T) {
+	trans := newTranslationStub(t)
+	resErr := NewServiceError(errStub, map[string]interface{}{"user_id": 42, "status": 200, "errors": "none"}).SetType("user_not_found")
+
+	rec, body := echoProblem(t, func() Response {
+		return NewResponse(trans).
+			WithError(resErr).
+			WithStatusCode(http.StatusNotFound).
+			WithProblemDetails("https://errors.example.com/")
+	})
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, map[string]interface{}{
+		"type":     "https://errors.example.com/user_not_found",
+		"title":    "Not Found",
+		"status":   float64(http.StatusNotFound),
+		"detail":   "stub",
+		"instance": "/users/42?expand=roles",
+		"user_id":  float64(42),
+	}, body)
+}
+
+func TestResource_WithProblemDetailsNativeError(t *testing.T) {
+	_, body := echoProblem(t, func() Response {
+		return NewResponse(nil).WithError(errStub).WithProblemDetails()
+	})
+
+	assert.Equal(t, "about:blank", body["type"])
+	assert.Equal(t, "Internal Server Error", body["title"])
+	assert.Equal(t, float64(http.StatusInternalServerError), body["status"])
+}
+
+func TestResource_WithProblemDetailsValidation(t *testing.T) {
+	trans := newTranslationStub(t)
+	err := validator.New().Struct(struct {
+		Name string `validate:"required"`
+	}{})
+
+	rec, body := echoProblem(t, func() Response {
+		return NewResponse(trans).
+			Validation(err).
+			WithProblemDetails()
+	})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "Unprocessable Entity", body["title"])
+	assert.Equal(t, map[string]interface{}{"Name": []interface{}{"name is required"}}, body["errors"])
+}
+
+func TestResource_WithProblemDetailsWithoutError(t *testing.T) {
+	trans := newTranslationStub(t)
+
+	rec, _ := echoProblem(t, func() Response {
+		return NewResponse(trans).WithMessage("greeting").WithProblemDetails()
+	})
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+	var normalized NormalizeResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &normalized))
+	assert.Equal(t, "Hello", *normalized.Message)
+}
+
+func TestProblemType(t *testing.T) {
+	r := &Resource{problemTypeBase: "https://errors.example.com/v1/"}
+
+	assert.Equal(t, "https://errors.example.com/v1/user_not_found", r.problemType("user_not_found"))
+	assert.Equal(t, "https://other.example.com/conflict", r.problemType("https://other.example.com/conflict"))
+	assert.Equal(t, "about:blank", r.problemType(""))
+	assert.Equal(t, "about:blank", (&Resource{}).problemType("user_not_found"))
+	assert.Equal(t, "https://other.example.com/conflict", (&Resource{}).problemType("https://other.example.com/conflict"))
+}
+
+func TestResource_WithProblemDetailsMultipleErrors(t *testing.T) {
+	notFound := NewServiceError(errors.New("user not found")).SetType("user_not_found").SetCode("users.not_found")
+	conflict := NewServiceError(errors.New("email taken")).SetType("email_taken").SetCode("users.email_taken")
+	mapping := NewStatusRegistry().
+		RegisterCode("users.not_found", http.StatusNotFound).
+		RegisterCode("users.email_taken", http.StatusConflict)
+
+	rec, body := echoProblem(t, func() Response {
+		return NewResponse(nil, mapping).WithError(errors.Join(notFound, conflict)).WithProblemDetails()
+	})
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "about:blank", body["type"])
+	assert.Equal(t, "users.email_taken", body["code"])
+	assert.Equal(t, "email taken", body["detail"])
+	assert.Len(t, body["errors"], 2)
+}
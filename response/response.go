@@ -13,6 +13,8 @@ import (
 const (
 	errTypeMsgSomethingIsWrong  = "server.errors.something_is_wrong"
 	errTypeInfoSomethingIsWrong = "something_is_wrong"
+	errTypeMsgValidation        = "server.errors.validation_failed"
+	errTypeInfoValidation       = "validation_failed"
 )
 
 type NormalizeResponse struct {
@@ -31,60 +33,120 @@ type Response interface {
 	Echo(ctx *gin.Context)
 	EchoPure() (statusCode int, response map[string]any)
 	WithStatusCode(statusCode int) Response
+	WithLanguage(lang string) Response
+	WithLocaleMeta() Response
+	WithProblemDetails(typeBase ...string) Response
+	WithProductionMode(hook ErrorHook) Response
 }
 
+// ErrorHook receives the errors hidden from clients in production mode.
+type ErrorHook func(err error)
+
 type Resource struct {
-	statusCodeMapping map[string]int
-	translation       translation.Translation
-	response          map[string]interface{}
-	message           *string
-	payload           *any
-	validation        *Validations
-	statusCode        *int
-	nativeError       error
-	responseError     Error
+	statusRegistry  *StatusRegistry
+	translation     translation.Translation
+	response        map[string]interface{}
+	language        string
+	localeMeta      bool
+	problem         bool
+	problemTypeBase string
+	production      bool
+	errorHook       ErrorHook
+	message         *string
+	messageArgs     map[string]interface{}
+	payload         *any
+	validation      error
+	statusCode      *int
+	errs            []error
 }
 
 type ErrorResponse struct {
 	TypeInfo   string                 `json:"type_info"`
+	Code       string                 `json:"code,omitempty"`
 	Status     int                    `json:"status"`
 	Detail     string                 `json:"detail"`
 	Attributes map[string]interface{} `json:"attributes,omitempty"`
+
+	// problemType is the type of a response.Error, empty for a native error.
+	problemType string
 }
 
 // NewResponse creates a new response.
+// The statuses of its errors are resolved with the registry, or with
+// DefaultStatusRegistry when none is given.
 func NewResponse(
 	trans translation.Translation,
-	statusCodeMappings ...map[string]int,
+	registries ...*StatusRegistry,
 ) Response {
-	statusCodeMapping := make(map[string]int)
+	registry := DefaultStatusRegistry
 
-	if len(statusCodeMappings) > 0 {
-		statusCodeMapping = statusCodeMappings[0]
+	if len(registries) > 0 && registries[0] != nil {
+		registry = registries[0]
 	}
 
 	return &Resource{
-		statusCodeMapping: statusCodeMapping,
-		translation:       trans,
-		response:          make(map[string]interface{}),
+		statusRegistry: registry,
+		translation:    trans,
+		response:       make(map[string]interface{}),
 	}
 }
 
 // Validation sets the validation error to be sent to the client.
+// The error is translated when the response is rendered. An error other than
+// validator.ValidationErrors renders no validation errors; use WithError for it.
 func (r *Resource) Validation(err error) Response {
-	v := newValidationTranslator(r.translation).translate(err)
-	r.validation = &v
+	r.validation = err
 	return r
 }
 
 // WithMessage sets the message to be sent to the client.
+// The message is translated when the response is rendered. If the arguments
+// contain translation.CountKey, its value selects the plural form of the message.
 func (r *Resource) WithMessage(message string, args ...map[string]interface{}) Response {
-	var arg map[string]interface{}
+	r.message = &message
 	if len(args) > 0 {
-		arg = args[0]
+		r.messageArgs = args[0]
 	}
-	trans := r.translation.Trans(message, arg)
-	r.message = &trans
+	return r
+}
+
+// WithLanguage sets the language messages and errors are translated to.
+// Echo uses the language negotiated by translation.Middleware unless one is set here.
+func (r *Resource) WithLanguage(lang string) Response {
+	r.language = lang
+	return r
+}
+
+// WithLocaleMeta makes the response describe the locale it is written in:
+// Echo sets the Content-Language header and adds the locale to the meta. A
+// meta given to WithMeta that is not a meta.Meta is left as is, without the
+// locale.
+func (r *Resource) WithLocaleMeta() Response {
+	r.localeMeta = true
+	return r
+}
+
+// WithProblemDetails renders errors as RFC 9457 problem details with the
+// application/problem+json content type. The type of an error is resolved
+// against typeBase, e.g. https://errors.example.com/ and user_not_found give
+// https://errors.example.com/user_not_found; without typeBase, a type that is
+// not an absolute URI is about:blank. Responses without errors are rendered as
+// usual.
+func (r *Resource) WithProblemDetails(typeBase ...string) Response {
+	r.problem = true
+	if len(typeBase) > 0 {
+		r.problemTypeBase = typeBase[0]
+	}
+	return r
+}
+
+// WithProductionMode hides the messages of errors without a public message,
+// i.e. native errors and those without SetPublicMessage, from clients: they are
+// rendered as the translated server.errors.something_is_wrong and reported to
+// hook, which may be nil.
+func (r *Resource) WithProductionMode(hook ErrorHook) Response {
+	r.production = true
+	r.errorHook = hook
 	return r
 }
 
@@ -100,19 +162,22 @@ func (r *Resource) WithPayload(data any) Response {
 	return r
 }
 
-// WithError sets the error to be sent to the client.
+// WithError adds an error to be sent to the client. Errors accumulate over
+// calls, and the errors joined with errors.Join are added one by one.
 func (r *Resource) WithError(err error) Response {
 	if err == nil {
 		return r
 	}
 
-	var e Error
-	if errors.As(err, &e) {
-		r.responseError = e
-	} else {
-		r.nativeError = err
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			r.WithError(err)
+		}
+		return r
 	}
 
+	r.errs = append(r.errs, err)
+
 	return r
 }
 
@@ -123,61 +188,72 @@ func (r *Resource) WithStatusCode(statusCode int) Response {
 }
 
 // EchoPure returns the response to be sent to the client.
+// Without an explicit status code, the status is the most severe, i.e. the
+// highest, of the statuses of the errors.
 func (r *Resource) EchoPure() (statusCode int, response map[string]any) {
-	if r.statusCode != nil {
-		statusCode = *r.statusCode
-	} else {
-		statusCode = r.getStatusMapping()
-	}
+	errs := r.errorResponses()
 
-	errInfo := errTypeInfoSomethingIsWrong
-	errDetail := errTypeMsgSomethingIsWrong
-	errAttributes := make(map[string]interface{})
+	var validations Validations
+	if r.validation != nil {
+		validations = newValidationTranslator(r.translation, r.language).translate(r.validation)
+	}
 
-	if r.nativeError != nil {
-		if r.nativeError.Error() != "" {
-			errInfo = r.nativeError.Error()
-			errDetail = r.nativeError.Error()
-		}
-	} else if r.responseError != nil {
-		if r.responseError.GetType() != "" {
-			errInfo = r.responseError.GetType()
-		}
-		if r.responseError.Error() != "" {
-			errDetail = r.responseError.Error()
-		}
-		errAttributes = r.responseError.GetAttributes()
+	if validations != nil && len(errs) > 0 {
+		errs = append(errs, r.validationErrorResponse(validations))
 	}
 
-	if r.translation != nil {
-		errDetail = r.translation.Trans(errDetail, errAttributes)
+	statusCode = http.StatusOK
+	if r.statusCode != nil {
+		statusCode = *r.statusCode
+	} else if len(errs) > 0 {
+		statusCode = errs[mostSevere(errs)].Status
+	} else if validations != nil && r.problem {
+		// A problem needs an error status.
+		statusCode = http.StatusUnprocessableEntity
 	}
 
-	if r.nativeError != nil || r.responseError != nil {
-		r.response["errors"] = []ErrorResponse{
-			{
-				TypeInfo:   errInfo,
-				Status:     statusCode,
-				Detail:     errDetail,
-				Attributes: errAttributes,
-			},
-		}
+	if len(errs) > 0 {
+		r.response["errors"] = errs
+	} else if r.validation != nil {
+		r.response["errors"] = validations
 	}
 
-	if r.validation != nil {
-		r.response["errors"] = *r.validation
+	if r.isProblem() {
+		problem := newProblemDetails(statusCode)
+		if len(errs) > 0 {
+			e := errs[mostSevere(errs)]
+			problem.setError(r.problemType(e.problemType), e.Code, e.Detail, e.Attributes)
+		}
+		if len(errs) > 1 || validations != nil {
+			problem["errors"] = r.response["errors"]
+		}
+		return statusCode, problem
 	}
 
 	if r.payload != nil {
 		r.response["data"] = *r.payload
 	}
 
-	if r.statusCode != nil {
-		statusCode = *r.statusCode
+	if r.message != nil {
+		message := *r.message
+		if count, ok := r.messageArgs[translation.CountKey]; ok && r.translation != nil {
+			message = r.translation.TransPlural(message, count, r.messageArgs, r.language)
+		} else if r.translation != nil {
+			message = r.translation.Trans(message, r.messageArgs, r.language)
+		}
+		r.response["message"] = message
 	}
 
-	if r.message != nil {
-		r.response["message"] = *r.message
+	if r.localeMeta && r.translation != nil {
+		if m, ok := r.response["meta"].(meta.Meta); ok || r.response["meta"] == nil {
+			locale := r.translation.Locale(r.language)
+			m.Locale = &meta.Locale{
+				Code:      locale.Tag.String(),
+				Direction: string(locale.Direction),
+				Name:      locale.Name,
+			}
+			r.response["meta"] = m
+		}
 	}
 
 	return statusCode, r.response
@@ -185,7 +261,24 @@ func (r *Resource) EchoPure() (statusCode int, response map[string]any) {
 
 // Echo sends the response to the client.
 func (r *Resource) Echo(ctx *gin.Context) {
+	if r.language == "" {
+		r.language = translation.LanguageFromContext(ctx)
+	}
+
+	if r.localeMeta && r.translation != nil {
+		ctx.Header("Content-Language", r.translation.Locale(r.language).Tag.String())
+	}
+
 	statusCode, rsp := r.EchoPure()
+	if r.isProblem() {
+		if _, ok := rsp["instance"]; !ok && ctx.Request != nil {
+			rsp["instance"] = ctx.Request.URL.RequestURI()
+		}
+		ctx.Header("Content-Type", problemContentType)
+		ctx.AbortWithStatusJSON(statusCode, rsp)
+		return
+	}
+
 	response := NormalizeResponse{
 		Data: func() *interface{} {
 			if rsp["data"] == nil {
@@ -223,33 +316,133 @@ func (r *Resource) Echo(ctx *gin.Context) {
 	ctx.AbortWithStatusJSON(statusCode, response)
 }
 
-// getStatusMapping returns the status code based on the error message.
-// If the error message is not found in the status code mapping, it returns 500.
-func (r *Resource) getStatusMapping() (statusCode int) {
-	switch {
-	case r.responseError != nil:
-		{
-			e := r.responseError
-			msg := e.Error()
-			if msg == "" {
-				return http.StatusInternalServerError
-			}
+// errorResponses returns the translated errors of the response.
+func (r *Resource) errorResponses() []ErrorResponse {
+	responses := make([]ErrorResponse, 0, len(r.errs))
+	for _, err := range r.errs {
+		responses = append(responses, r.errorResponse(err))
+	}
 
-			if val, ok := r.statusCodeMapping[msg]; !ok {
-				statusCode = http.StatusInternalServerError
-			} else {
-				statusCode = val
-			}
+	return responses
+}
+
+// errorResponse returns the translated error.
+func (r *Resource) errorResponse(err error) ErrorResponse {
+	res := ErrorResponse{
+		TypeInfo:   errTypeInfoSomethingIsWrong,
+		Detail:     errTypeMsgSomethingIsWrong,
+		Attributes: make(map[string]interface{}),
+	}
+
+	native := false
+	var e Error
+	if errors.As(err, &e) {
+		if e.GetType() != "" {
+			res.TypeInfo = e.GetType()
+			res.problemType = e.GetType()
 		}
-	case r.nativeError != nil:
-		{
-			statusCode = http.StatusInternalServerError
+		message, public := publicMessage(e)
+		if r.production && !public {
+			// The Error text of a wrapped error may hold internal details.
+			r.reportError(err)
+		} else if message != "" {
+			res.Detail = message
+		}
+		res.Attributes = e.GetAttributes()
+	} else if r.production {
+		r.reportError(err)
+	} else if err.Error() != "" {
+		res.TypeInfo = err.Error()
+		res.Detail = err.Error()
+		native = true
+	}
+
+	var coder Coder
+	if errors.As(err, &coder) {
+		res.Code = coder.GetCode()
+	}
+
+	if r.statusCode != nil {
+		res.Status = *r.statusCode
+	} else {
+		res.Status, _ = r.statusRegistry.Status(err)
+	}
+
+	if r.translation != nil && native {
+		res.Detail = r.translateOrKeep(res.Detail)
+	} else if r.translation != nil {
+		res.Detail = r.translation.Trans(res.Detail, res.Attributes, r.language)
+	}
+
+	return res
+}
+
+// translateOrKeep translates the message of a native error when the catalogs
+// have it as a message ID, and otherwise returns it as is. The message is not
+// reported missing, since it is usually not meant to be a message ID and may
+// hold internal details.
+func (r *Resource) translateOrKeep(message string) string {
+	if _, ok := r.translation.Catalog(r.language)[message]; !ok {
+		return message
+	}
+
+	return r.translation.Trans(message, nil, r.language)
+}
+
+// publicMessage returns the message of e shown to clients, and whether it is
+// a public message rather than the Error text.
+func publicMessage(e Error) (string, bool) {
+	if s, ok := e.(*ServiceError); ok {
+		if message := s.publicMessage(); message != "" {
+			return message, true
 		}
-	default:
-		{
-			statusCode = http.StatusOK
+		return s.Error(), false
+	}
+
+	if p, ok := e.(PublicMessager); ok {
+		return p.GetPublicMessage(), true
+	}
+
+	return e.Error(), false
+}
+
+// reportError passes an error hidden from clients to the error hook.
+func (r *Resource) reportError(err error) {
+	if r.errorHook != nil {
+		r.errorHook(err)
+	}
+}
+
+// validationErrorResponse returns the validation errors as an error, for a
+// response that also has other errors.
+func (r *Resource) validationErrorResponse(validations Validations) ErrorResponse {
+	res := ErrorResponse{
+		TypeInfo:    errTypeInfoValidation,
+		Status:      http.StatusUnprocessableEntity,
+		Detail:      errTypeMsgValidation,
+		Attributes:  map[string]interface{}{"fields": validations},
+		problemType: errTypeInfoValidation,
+	}
+
+	if r.statusCode != nil {
+		res.Status = *r.statusCode
+	}
+
+	if r.translation != nil {
+		res.Detail = r.translation.Trans(res.Detail, nil, r.language)
+	}
+
+	return res
+}
+
+// mostSevere returns the index of the first error with the highest status.
+func mostSevere(errs []ErrorResponse) int {
+	severest := 0
+	for i, err := range errs {
+		if err.Status > errs[severest].Status {
+			severest = i
 		}
 	}
 
-	return statusCode
+	return severest
 }
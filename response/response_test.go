@@ -0,0 +1,179 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ghaninia/gokit/meta"
+	"github.com/ghaninia/gokit/translation"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTranslationStub(t *testing.T) translation.Translation {
+	dir := t.TempDir()
+	files := map[string]string{
+		"en.json": `{"greeting": "Hello", "validation.required": "{{.attribute}} is required", "attributes.Name": "name"}`,
+		"fa.json": `{"greeting": "سلام", "validation.required": "{{.attribute}} الزامی است", "attributes.Name": "نام"}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return translation.NewTranslation(translation.Config{Locale: "en", PathLocale: dir})
+}
+
+func TestResource_WithLanguage(t *testing.T) {
+	trans := newTranslationStub(t)
+
+	_, resp := NewResponse(trans).WithMessage("greeting").WithLanguage("fa").EchoPure()
+	assert.Equal(t, "سلام", resp["message"])
+
+	_, resp = NewResponse(trans).WithMessage("greeting").EchoPure()
+	assert.Equal(t, "Hello", resp["message"])
+}
+
+func TestResource_EchoNegotiatedLanguage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	trans := newTranslationStub(t)
+
+	err := validator.New().Struct(struct {
+		Name string `validate:"required"`
+	}{})
+
+	router := gin.New()
+	router.Use(translation.Middleware(trans))
+	router.GET("/", func(ctx *gin.Context) {
+		NewResponse(trans).
+			WithMessage("greeting").
+			Validation(err).
+			WithStatusCode(http.StatusUnprocessableEntity).
+			Echo(ctx)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "fa-IR,en;q=0.5")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var body NormalizeResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "سلام", *body.Message)
+	assert.Equal(t, map[string]interface{}{"Name": []interface{}{"نام الزامی است"}}, *body.Errors)
+}
+
+func TestResource_WithPluralMessage(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "en.json"), []byte(`{"created": {"one": "{{.count}} user created", "other": "{{.count}} users created"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	trans := translation.NewTranslation(translation.Config{Locale: "en", PathLocale: dir})
+
+	_, resp := NewResponse(trans).WithMessage("created", map[string]interface{}{"count": 1}).EchoPure()
+	assert.Equal(t, "1 user created", resp["message"])
+
+	_, resp = NewResponse(trans).WithMessage("created", map[string]interface{}{"count": 3}).EchoPure()
+	assert.Equal(t, "3 users created", resp["message"])
+}
+
+func TestResource_WithNativeError(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "en.json"), []byte(`{"greeting": "Hello"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	report := translation.NewMissingReport()
+	trans := translation.NewTranslation(translation.Config{Locale: "en", PathLocale: dir, Strict: true, OnMissing: report.Handle})
+
+	// The message of a native error is translated when it is a message ID,
+	// and kept as is without being reported missing otherwise.
+	var resp map[string]any
+	assert.NotPanics(t, func() {
+		_, resp = NewResponse(trans).WithError(errors.New("pq: password authentication failed")).EchoPure()
+	})
+	assert.Equal(t, "pq: password authentication failed", resp["errors"].([]ErrorResponse)[0].Detail)
+
+	_, resp = NewResponse(trans).WithError(errors.New("greeting")).EchoPure()
+	assert.Equal(t, "Hello", resp["errors"].([]ErrorResponse)[0].Detail)
+	assert.Empty(t, report.Keys())
+}
+
+func TestResource_WithLocaleMeta(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	trans := newTranslationStub(t)
+
+	router := gin.New()
+	router.Use(translation.Middleware(trans))
+	router.GET("/", func(ctx *gin.Context) {
+		NewResponse(trans).
+			WithMessage("greeting").
+			WithLocaleMeta().
+			Echo(ctx)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/?lang=fa-IR", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var body NormalizeResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "fa", rec.Header().Get("Content-Language"))
+	assert.Equal(t, &meta.Meta{
+		Locale: &meta.Locale{Code: "fa", Direction: "rtl", Name: "فارسی"},
+	}, body.Meta)
+	assert.NotContains(t, rec.Body.String(), "pagination")
+}
+
+func TestResource_WithLocaleMetaKeepsMeta(t *testing.T) {
+	trans := newTranslationStub(t)
+	pagination := meta.Pagination{Page: 1, PerPage: 10, TotalCount: 3}
+
+	_, resp := NewResponse(trans).WithMeta(meta.Meta{Pagination: pagination}).WithLocaleMeta().EchoPure()
+	assert.Equal(t, meta.Meta{
+		Pagination: pagination,
+		Locale:     &meta.Locale{Code: "en", Direction: "ltr", Name: "English"},
+	}, resp["meta"])
+
+	buf, err := json.Marshal(resp["meta"])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"pagination": {"page": 1, "perPage": 10, "pageCount": 0, "totalCount": 3},
+		"locale": {"code": "en", "direction": "ltr", "name": "English"}
+	}`, string(buf))
+
+	_, resp = NewResponse(trans).WithMessage("greeting").EchoPure()
+	assert.Nil(t, resp["meta"])
+
+	_, resp = NewResponse(trans).WithMeta(map[string]int{"total": 3}).WithLocaleMeta().EchoPure()
+	assert.Equal(t, map[string]int{"total": 3}, resp["meta"])
+}
+
+func TestResource_EchoPseudoLocale(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "en.json"), []byte(`{"greeting": "Hello"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	trans := translation.NewTranslation(translation.Config{Locale: "en", PseudoLocale: "en-XA", PathLocale: dir})
+
+	router := gin.New()
+	router.Use(translation.Middleware(trans))
+	router.GET("/", func(ctx *gin.Context) {
+		NewResponse(trans).WithMessage("greeting").Echo(ctx)
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?lang=en-XA", nil))
+
+	var body NormalizeResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "[Ĥéļļö ··]", *body.Message)
+}
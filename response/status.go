@@ -0,0 +1,77 @@
+package response
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// DefaultStatusRegistry is the registry of the responses created without one.
+var DefaultStatusRegistry = NewStatusRegistry()
+
+// StatusRegistry maps errors to HTTP statuses by their code, see Coder, or by
+// the sentinel errors they match with errors.Is. It is safe for concurrent use.
+type StatusRegistry struct {
+	mu        sync.RWMutex
+	codes     map[string]int
+	sentinels []sentinelStatus
+}
+
+type sentinelStatus struct {
+	err    error
+	status int
+}
+
+// NewStatusRegistry creates an empty registry.
+func NewStatusRegistry() *StatusRegistry {
+	return &StatusRegistry{
+		codes: make(map[string]int),
+	}
+}
+
+// RegisterCode maps the errors with the code to status.
+func (r *StatusRegistry) RegisterCode(code string, status int) *StatusRegistry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.codes[code] = status
+	return r
+}
+
+// RegisterError maps the errors matching err with errors.Is to status.
+// Sentinels are matched in the order they are registered.
+func (r *StatusRegistry) RegisterError(err error, status int) *StatusRegistry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sentinels = append(r.sentinels, sentinelStatus{err: err, status: status})
+	return r
+}
+
+// Status returns the status of err: its own status when it is a StatusCoder,
+// then the status of its code, then that of the first sentinel it matches.
+// Without any, it returns 500 and false.
+func (r *StatusRegistry) Status(err error) (int, bool) {
+	var statusCoder StatusCoder
+	if errors.As(err, &statusCoder) && statusCoder.GetStatus() != 0 {
+		return statusCoder.GetStatus(), true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var coder Coder
+	if errors.As(err, &coder) {
+		if status, ok := r.codes[coder.GetCode()]; ok {
+			return status, true
+		}
+	}
+
+	for _, sentinel := range r.sentinels {
+		if errors.Is(err, sentinel.err) {
+			return sentinel.status, true
+		}
+	}
+
+	return http.StatusInternalServerError, false
+}
@@ -0,0 +1,59 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errNotFoundStub = errors.New("record not found")
+
+func TestStatusRegistry_Status(t *testing.T) {
+	registry := NewStatusRegistry().
+		RegisterCode("users.not_found", http.StatusNotFound).
+		RegisterError(errNotFoundStub, http.StatusGone).
+		RegisterError(errStub, http.StatusBadRequest)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		found  bool
+	}{
+		{name: "own status", err: NewServiceError(errStub).SetCode("users.not_found").SetStatus(http.StatusForbidden), status: http.StatusForbidden, found: true},
+		{name: "code", err: NewServiceError(errStub).SetCode("users.not_found"), status: http.StatusNotFound, found: true},
+		{name: "sentinel", err: errNotFoundStub, status: http.StatusGone, found: true},
+		{name: "wrapped sentinel", err: fmt.Errorf("find user: %w", errNotFoundStub), status: http.StatusGone, found: true},
+		{name: "unknown code", err: NewServiceError(errors.New("x")).SetCode("users.unknown"), status: http.StatusInternalServerError},
+		{name: "unknown", err: errors.New("record not found"), status: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, found := registry.Status(tt.err)
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, tt.found, found)
+		})
+	}
+}
+
+func TestNewResponse_StatusRegistry(t *testing.T) {
+	registry := NewStatusRegistry().RegisterError(errNotFoundStub, http.StatusNotFound)
+
+	statusCode, resp := NewResponse(nil, registry).WithError(fmt.Errorf("find user: %w", errNotFoundStub)).EchoPure()
+	assert.Equal(t, http.StatusNotFound, statusCode)
+	assert.Equal(t, http.StatusNotFound, resp["errors"].([]ErrorResponse)[0].Status)
+
+	statusCode, _ = NewResponse(nil).WithError(errNotFoundStub).EchoPure()
+	assert.Equal(t, http.StatusInternalServerError, statusCode)
+
+	DefaultStatusRegistry.RegisterCode("stub.default", http.StatusTeapot)
+	defer delete(DefaultStatusRegistry.codes, "stub.default")
+
+	statusCode, resp = NewResponse(nil).WithError(NewServiceError(errStub).SetCode("stub.default")).EchoPure()
+	assert.Equal(t, http.StatusTeapot, statusCode)
+	assert.Equal(t, "stub.default", resp["errors"].([]ErrorResponse)[0].Code)
+}
@@ -16,14 +16,22 @@ type ValidationError struct {
 
 type validation struct {
 	Translation translation.Translation
+	Language    string
 }
 
 func newValidationTranslator(
 	t translation.Translation,
+	languages ...string,
 ) *validation {
-	return &validation{
+	v := &validation{
 		Translation: t,
 	}
+
+	if len(languages) > 0 {
+		v.Language = languages[0]
+	}
+
+	return v
 }
 
 func (v validation) translate(err error) Validations {
@@ -40,9 +48,10 @@ func (v validation) translate(err error) Validations {
 				return v.Translation.Trans(
 					"validation."+err.Tag(),
 					map[string]interface{}{
-						"attribute": v.Translation.Trans("attributes."+err.Field(), nil),
+						"attribute": v.Translation.Trans("attributes."+err.Field(), nil, v.Language),
 						err.Tag():   err.Param(),
 					},
+					v.Language,
 				)
 			}(),
 		})
@@ -0,0 +1,145 @@
+package translation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+const (
+	defaultCatalogLanguageParam = "lang"
+	defaultCatalogPrefixQuery   = "prefix"
+	defaultCatalogMaxAge        = 5 * time.Minute
+)
+
+type CatalogConfig struct {
+	// LanguageParam is the route parameter holding the language, e.g. /locales/:lang.
+	// Defaults to "lang". Without it, the language negotiated by Middleware is served.
+	LanguageParam string
+	// PrefixQuery is the query parameter filtering messages by key prefix. Defaults to "prefix".
+	PrefixQuery string
+	// MaxAge is how long clients may cache a catalog. Defaults to 5 minutes.
+	MaxAge time.Duration
+}
+
+// CatalogHandler serves the catalog of a language as JSON, in the same format
+// as the JSON message files: a simple message is a string and a plural message
+// an object of its plural forms. The messages are merged along the fallback
+// chain, see Translation.Catalog, so the client gets every key the server
+// would resolve. Responses carry an ETag and are revalidated with If-None-Match.
+func CatalogHandler(t Translation, configs ...CatalogConfig) gin.HandlerFunc {
+	config := CatalogConfig{}
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+
+	if config.LanguageParam == "" {
+		config.LanguageParam = defaultCatalogLanguageParam
+	}
+
+	if config.PrefixQuery == "" {
+		config.PrefixQuery = defaultCatalogPrefixQuery
+	}
+
+	if config.MaxAge == 0 {
+		config.MaxAge = defaultCatalogMaxAge
+	}
+
+	return func(ctx *gin.Context) {
+		lang := ctx.Param(config.LanguageParam)
+		if lang == "" {
+			lang = GetLanguage(ctx)
+			ctx.Header("Vary", "Accept-Language, Cookie")
+		}
+
+		prefix := ctx.Query(config.PrefixQuery)
+		catalog := make(map[string]interface{})
+		for id, message := range t.Catalog(lang) {
+			if strings.HasPrefix(id, prefix) {
+				catalog[id] = catalogMessage(message)
+			}
+		}
+
+		body, err := json.Marshal(catalog)
+		if err != nil {
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		sum := sha256.Sum256(body)
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+		ctx.Header("ETag", etag)
+		ctx.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(config.MaxAge.Seconds())))
+		ctx.Header("Content-Language", t.Locale(lang).Tag.String())
+
+		if etagMatches(ctx.Request.Header.Values("If-None-Match"), etag) {
+			ctx.Status(http.StatusNotModified)
+			return
+		}
+
+		ctx.Data(http.StatusOK, "application/json; charset=utf-8", body)
+	}
+}
+
+// etagMatches reports whether one of the If-None-Match header values matches
+// etag. Each value is "*" or a list of entity tags, compared weakly as RFC 9110
+// section 13.1.2 requires: a W/ prefix on either side is ignored.
+func etagMatches(values []string, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, value := range values {
+		for {
+			value = strings.TrimLeft(value, " \t,")
+			if value == "" {
+				break
+			}
+
+			if value[0] == '*' {
+				return true
+			}
+
+			value = strings.TrimPrefix(value, "W/")
+			if value == "" || value[0] != '"' {
+				break
+			}
+
+			end := strings.IndexByte(value[1:], '"')
+			if end < 0 {
+				break
+			}
+
+			if value[:end+2] == etag {
+				return true
+			}
+			value = value[end+2:]
+		}
+	}
+
+	return false
+}
+
+// catalogMessage returns the JSON value of message.
+func catalogMessage(message *i18n.Message) interface{} {
+	forms := make(map[string]string)
+	for form, text := range map[string]string{
+		"zero": message.Zero, "one": message.One, "two": message.Two,
+		"few": message.Few, "many": message.Many, "other": message.Other,
+	} {
+		if text != "" {
+			forms[form] = text
+		}
+	}
+
+	if _, ok := forms["other"]; ok && len(forms) == 1 {
+		return message.Other
+	}
+
+	return forms
+}
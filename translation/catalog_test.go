@@ -0,0 +1,108 @@
+package translation
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// catalogFiles are the locale files of the catalog tests.
+var catalogFiles = map[string]string{
+	"fa.json": `{"billing.total": "مبلغ کل", "billing.items": {"one": "{{.count}} قلم", "other": "{{.count}} قلم"}}`,
+	"en.json": `{"billing.total": "Total", "billing.tax": "Tax", "greeting": "Hello"}`,
+}
+
+// catalogRouter serves the catalogs of trans.
+func catalogRouter(trans Translation) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware(trans))
+	router.GET("/locales/:lang", CatalogHandler(trans))
+	router.GET("/locale", CatalogHandler(trans))
+
+	return router
+}
+
+func TestCatalogHandler(t *testing.T) {
+	router := catalogRouter(newTranslationStub(t, Config{Locale: "fa"}, catalogFiles))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/locales/fa-IR?prefix=billing.", nil))
+
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "fa", rec.Header().Get("Content-Language"))
+	assert.Equal(t, "public, max-age=300", rec.Header().Get("Cache-Control"))
+	assert.NotEmpty(t, rec.Header().Get("ETag"))
+	assert.Equal(t, map[string]interface{}{
+		"billing.total": "مبلغ کل",
+		"billing.tax":   "Tax",
+		"billing.items": map[string]interface{}{"one": "{{.count}} قلم", "other": "{{.count}} قلم"},
+	}, body)
+}
+
+func TestCatalogHandler_NegotiatedLanguage(t *testing.T) {
+	router := catalogRouter(newTranslationStub(t, Config{Locale: "fa"}, catalogFiles))
+
+	req := httptest.NewRequest(http.MethodGet, "/locale", nil)
+	req.Header.Set("Accept-Language", "en-US")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "en", rec.Header().Get("Content-Language"))
+	assert.Equal(t, "Accept-Language, Cookie", rec.Header().Get("Vary"))
+	assert.Equal(t, "Total", body["billing.total"])
+	assert.Len(t, body, 3)
+}
+
+func TestCatalogHandler_NotModified(t *testing.T) {
+	router := catalogRouter(newTranslationStub(t, Config{Locale: "fa"}, catalogFiles))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/locales/en", nil))
+	etag := rec.Header().Get("ETag")
+
+	req := httptest.NewRequest(http.MethodGet, "/locales/en", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/locales/en", nil)
+	req.Header.Set("If-None-Match", `"stale", W/`+etag)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/locales/fa", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestEtagMatches(t *testing.T) {
+	etag := `"abc"`
+
+	assert.True(t, etagMatches([]string{`"abc"`}, etag))
+	assert.True(t, etagMatches([]string{`W/"abc"`}, etag))
+	assert.True(t, etagMatches([]string{`"x", W/"abc"`}, etag))
+	assert.True(t, etagMatches([]string{`"x,y"`, ` "abc" `}, etag))
+	assert.True(t, etagMatches([]string{"*"}, etag))
+	assert.False(t, etagMatches(nil, etag))
+	assert.False(t, etagMatches([]string{`"abcd", "ab"`}, etag))
+	assert.False(t, etagMatches([]string{`abc`}, etag))
+	assert.False(t, etagMatches([]string{`"abc`}, etag))
+	assert.False(t, etagMatches([]string{"W/"}, etag))
+}
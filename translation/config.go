@@ -1,7 +1,46 @@
 package translation
 
+import "io/fs"
+
 type Config struct {
-	Locale         string
+	// Locale is the language used when a call does not ask for one.
+	Locale string
+	// FallbackLocale is tried when a message is missing in the requested
+	// language and its parents, before the bundle default.
 	FallbackLocale string
-	PathLocale     string
+	// BundleLocale is the default language of the bundle and the last resort
+	// of every lookup. Defaults to English.
+	BundleLocale string
+	// PathLocale is a directory on disk to load message files from. When FS is
+	// also set, its files are loaded after FS so they override the embedded ones.
+	PathLocale string
+	// FS is a file system, such as an embed.FS, to load message files from.
+	FS fs.FS
+	// Namespaces prefixes the message IDs of the files in a subdirectory with
+	// its path, e.g. the messages of billing/fa.json become billing.*. Use
+	// Translation.Namespace to translate within one.
+	Namespaces bool
+	// Store provides messages kept outside of the message files, such as a
+	// GormStore. They override the messages of the files and are loaded again
+	// on Reload.
+	Store Store
+	// PseudoLocale enables pseudo-localization for a language, such as en-XA:
+	// messages requested in it are resolved along its fallback chain, then
+	// accented, expanded by about 30% and wrapped in brackets, so that
+	// hardcoded and truncated strings stand out. It must be a language tag
+	// known to golang.org/x/text, such as the pseudo-locales en-XA and ar-XB;
+	// an unknown tag such as xx-PS is logged as an error and leaves
+	// pseudo-localization off.
+	PseudoLocale string
+	// OnReloadError is called by Watch when rebuilding the bundle fails.
+	OnReloadError func(err error)
+	// OnMissing is called when a message is missing in every language of the
+	// fallback chain, e.g. to collect a MissingReport or emit metrics.
+	OnMissing MissingHandler
+	// Logger receives the events of the package. Defaults to slog.Default();
+	// use NopLogger to silence them.
+	Logger Logger
+	// Strict makes a missing message panic with a *MissingTranslationError,
+	// which is useful to catch incomplete catalogs in tests.
+	Strict bool
 }
@@ -0,0 +1,42 @@
+package translation
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// languageContextKey is the context.Context key the language is stored under.
+type languageContextKey struct{}
+
+// WithLanguage returns a copy of ctx carrying lang, so that code outside of
+// gin handlers, such as emails and background jobs, can translate in the
+// language captured from the request.
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageContextKey{}, lang)
+}
+
+// LanguageFromContext returns the language carried by ctx, or an empty string.
+// A *gin.Context is also accepted, in which case the language negotiated by
+// Middleware is returned.
+func LanguageFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	if c, ok := ctx.(*gin.Context); ok {
+		if lang := GetLanguage(c); lang != "" || c.Request == nil {
+			return lang
+		}
+		ctx = c.Request.Context()
+	}
+
+	lang, _ := ctx.Value(languageContextKey{}).(string)
+
+	return lang
+}
+
+// TransCtx translates a message in the language carried by ctx.
+func (t *translation) TransCtx(ctx context.Context, key string, args map[string]interface{}) string {
+	return t.Trans(key, args, LanguageFromContext(ctx))
+}
@@ -0,0 +1,47 @@
+package translation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLanguageFromContext(t *testing.T) {
+	assert.Equal(t, "", LanguageFromContext(context.Background()))
+	assert.Equal(t, "fa", LanguageFromContext(WithLanguage(context.Background(), "fa")))
+}
+
+func TestTranslation_TransCtx(t *testing.T) {
+	trans := newTranslationStub(t, Config{Locale: "en"}, map[string]string{
+		"en.json": `{"greeting": "Hello {{.name}}"}`,
+		"fa.json": `{"greeting": "سلام {{.name}}"}`,
+	})
+	args := map[string]interface{}{"name": "Gopher"}
+
+	assert.Equal(t, "Hello Gopher", trans.TransCtx(context.Background(), "greeting", args))
+	assert.Equal(t, "سلام Gopher", trans.TransCtx(WithLanguage(context.Background(), "fa"), "greeting", args))
+}
+
+func TestMiddleware_RequestContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	trans := newTranslationStub(t, Config{Locale: "fa"}, negotiationFiles)
+
+	var fromRequest, fromGin string
+	router := gin.New()
+	router.Use(Middleware(trans))
+	router.GET("/", func(ctx *gin.Context) {
+		fromRequest = LanguageFromContext(ctx.Request.Context())
+		fromGin = LanguageFromContext(ctx)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "de")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "de", fromRequest)
+	assert.Equal(t, "de", fromGin)
+}
@@ -0,0 +1,332 @@
+package translation
+
+import (
+	"fmt"
+	"sync"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nicksnyder/go-i18n/v2/i18n/template"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// now returns the current time, it is replaced in tests.
+var now = time.Now
+
+// persianMonths are the months of the Jalali (Solar Hijri) calendar.
+var persianMonths = [12]string{
+	"فروردین", "اردیبهشت", "خرداد", "تیر", "مرداد", "شهریور",
+	"مهر", "آبان", "آذر", "دی", "بهمن", "اسفند",
+}
+
+// relativeUnits are the units of FormatRelativeTime, largest first.
+var relativeUnits = []struct {
+	name string
+	size time.Duration
+}{
+	{name: "year", size: 365 * 24 * time.Hour},
+	{name: "month", size: 30 * 24 * time.Hour},
+	{name: "day", size: 24 * time.Hour},
+	{name: "hour", size: time.Hour},
+	{name: "minute", size: time.Minute},
+	{name: "second", size: time.Second},
+}
+
+// relativeTimeBundle holds the built-in messages of FormatRelativeTime. They can
+// be overridden in the catalogs under time.relative.now and
+// time.relative.<unit>.<past|future>.
+var relativeTimeBundle = func() *i18n.Bundle {
+	bundle := i18n.NewBundle(language.English)
+	bundle.MustAddMessages(language.English, relativeTimeMessages(
+		"now", "{{.count}} %s ago", "in {{.count}} %s",
+		map[string][2]string{
+			"year": {"year", "years"}, "month": {"month", "months"}, "day": {"day", "days"},
+			"hour": {"hour", "hours"}, "minute": {"minute", "minutes"}, "second": {"second", "seconds"},
+		},
+	)...)
+	bundle.MustAddMessages(language.Persian, relativeTimeMessages(
+		"اکنون", "{{.count}} %s پیش", "{{.count}} %s بعد",
+		map[string][2]string{
+			"year": {"سال", "سال"}, "month": {"ماه", "ماه"}, "day": {"روز", "روز"},
+			"hour": {"ساعت", "ساعت"}, "minute": {"دقیقه", "دقیقه"}, "second": {"ثانیه", "ثانیه"},
+		},
+	)...)
+
+	return bundle
+}()
+
+// relativeTimeMatcher matches a language against those of relativeTimeBundle.
+var relativeTimeMatcher = language.NewMatcher([]language.Tag{language.English, language.Persian})
+
+// relativeTimeLanguage returns the language of relativeTimeBundle used for tag:
+// its closest match, or English unless the match is at least highly confident,
+// so that Arabic does not get Persian text.
+func relativeTimeLanguage(tag language.Tag) language.Tag {
+	_, index, confidence := relativeTimeMatcher.Match(tag)
+	if confidence < language.High {
+		return language.English
+	}
+
+	return []language.Tag{language.English, language.Persian}[index]
+}
+
+// relativeTimeMessages builds the relative time messages of a language from the
+// singular and plural name of every unit.
+func relativeTimeMessages(now, past, future string, units map[string][2]string) []*i18n.Message {
+	messages := []*i18n.Message{{ID: "time.relative.now", Other: now}}
+	for unit, names := range units {
+		messages = append(messages,
+			&i18n.Message{
+				ID:    "time.relative." + unit + ".past",
+				One:   fmt.Sprintf(past, names[0]),
+				Other: fmt.Sprintf(past, names[1]),
+			},
+			&i18n.Message{
+				ID:    "time.relative." + unit + ".future",
+				One:   fmt.Sprintf(future, names[0]),
+				Other: fmt.Sprintf(future, names[1]),
+			},
+		)
+	}
+
+	return messages
+}
+
+// templateParser parses message templates with the formatting functions of a
+// language, and resolves their ICU choices with its plural rules. go-i18n does
+// not cache templates parsed with functions, so they are cached here by source.
+type templateParser struct {
+	tag    language.Tag
+	funcs  texttemplate.FuncMap
+	number func(value interface{}) string
+	cache  sync.Map // string -> template.ParsedTemplate
+}
+
+func (p *templateParser) Cacheable() bool {
+	return false
+}
+
+func (p *templateParser) Parse(src, leftDelim, rightDelim string) (template.ParsedTemplate, error) {
+	key := leftDelim + "\x00" + rightDelim + "\x00" + src
+	if parsed, ok := p.cache.Load(key); ok {
+		return parsed.(template.ParsedTemplate), nil
+	}
+
+	if !hasChoice(src) {
+		return p.parseTemplate(src, leftDelim, rightDelim)
+	}
+
+	parsed, err := p.parseChoices(src, leftDelim, rightDelim)
+	if err != nil {
+		return nil, err
+	}
+	p.cache.Store(key, parsed)
+
+	return parsed, nil
+}
+
+// parseTemplate parses a message template without ICU choices, or the text
+// of a case of a choice.
+func (p *templateParser) parseTemplate(src, leftDelim, rightDelim string) (template.ParsedTemplate, error) {
+	key := leftDelim + "\x00" + rightDelim + "\x00" + src
+	if parsed, ok := p.cache.Load(key); ok {
+		return parsed.(template.ParsedTemplate), nil
+	}
+
+	parsed, err := (&template.TextParser{Funcs: p.funcs}).Parse(src, leftDelim, rightDelim)
+	if err != nil {
+		return nil, err
+	}
+	p.cache.Store(key, parsed)
+
+	return parsed, nil
+}
+
+// parser returns the cached template parser of tag.
+func (s *snapshot) parser(t *translation, tag language.Tag) *templateParser {
+	if parser, ok := s.parsers.Load(tag); ok {
+		return parser.(*templateParser)
+	}
+
+	parser, _ := s.parsers.LoadOrStore(tag, &templateParser{
+		tag:   tag,
+		funcs: t.templateFuncs(tag.String()),
+		number: func(value interface{}) string {
+			return t.FormatNumber(value, tag.String())
+		},
+	})
+
+	return parser.(*templateParser)
+}
+
+// templateFuncs returns the formatting functions available in the message
+// templates of lang, e.g. {{number .total}} or {{currency .price "IRR"}}.
+func (t *translation) templateFuncs(lang string) texttemplate.FuncMap {
+	return texttemplate.FuncMap{
+		"number": func(value interface{}) string {
+			return t.FormatNumber(value, lang)
+		},
+		"percent": func(value interface{}) string {
+			return t.FormatPercent(value, lang)
+		},
+		"currency": func(amount interface{}, code string) string {
+			return t.FormatCurrency(amount, code, lang)
+		},
+		"date": func(value time.Time) string {
+			return t.FormatDate(value, lang)
+		},
+		"relativeTime": func(value time.Time) string {
+			return t.FormatRelativeTime(value, lang)
+		},
+	}
+}
+
+// formatLanguage returns the language values are formatted in: the first
+// loaded language of the fallback chain of the requested one.
+func (t *translation) formatLanguage(languages ...string) language.Tag {
+	lang := t.requestedLanguage(languages...)
+
+	if chain := t.loadedChain(t.snapshot(), lang); len(chain) > 0 {
+		return chain[0]
+	}
+
+	if tag, err := language.Parse(lang); err == nil {
+		return tag
+	}
+
+	return t.bundleLanguage()
+}
+
+// printer returns a printer of tag. The region is dropped since x/text falls
+// back to the root locale, and so to Latin digits, for most regional variants.
+func printer(tag language.Tag) *message.Printer {
+	base, _ := tag.Base()
+	script, _ := tag.Script()
+
+	if composed, err := language.Compose(base, script); err == nil {
+		tag = composed
+	}
+
+	return message.NewPrinter(tag)
+}
+
+// FormatNumber formats a number with the digits and separators of the language,
+// e.g. 1234.5 is ۱٬۲۳۴٫۵ in Persian.
+func (t *translation) FormatNumber(value interface{}, languages ...string) string {
+	return printer(t.formatLanguage(languages...)).Sprint(number.Decimal(value))
+}
+
+// FormatPercent formats a ratio as a percentage, e.g. 0.25 is 25%.
+func (t *translation) FormatPercent(value interface{}, languages ...string) string {
+	return printer(t.formatLanguage(languages...)).Sprint(number.Percent(value))
+}
+
+// FormatCurrency formats an amount of the currency with the ISO 4217 code, or
+// of the currency of the language's region when code is empty.
+func (t *translation) FormatCurrency(amount interface{}, code string, languages ...string) string {
+	tag := t.formatLanguage(languages...)
+
+	unit, err := currency.ParseISO(code)
+	if code == "" {
+		unit, _ = currency.FromTag(tag)
+	} else if err != nil {
+		t.logger().Warn("translation: invalid currency code", "currency", code, "error", err)
+		return t.FormatNumber(amount, languages...)
+	}
+
+	return printer(tag).Sprint(currency.Symbol(unit.Amount(amount)))
+}
+
+// FormatDate formats the date of value. Persian uses the Jalali calendar, e.g.
+// ۱ فروردین ۱۴۰۳, English a long date such as March 20, 2024, and other
+// languages a numeric date with their own digits.
+func (t *translation) FormatDate(value time.Time, languages ...string) string {
+	tag := t.formatLanguage(languages...)
+	p := printer(tag)
+	digits := func(n, width int) string {
+		return p.Sprint(number.Decimal(n, number.NoSeparator(), number.MinIntegerDigits(width)))
+	}
+
+	switch base, _ := tag.Base(); base.String() {
+	case "fa":
+		year, month, day := toJalali(value.Year(), int(value.Month()), value.Day())
+		return digits(day, 1) + " " + persianMonths[month-1] + " " + digits(year, 1)
+	case "en":
+		return value.Format("January 2, 2006")
+	default:
+		return digits(value.Year(), 4) + "-" + digits(int(value.Month()), 2) + "-" + digits(value.Day(), 2)
+	}
+}
+
+// FormatRelativeTime formats value relative to now, e.g. 3 days ago or in 2 hours.
+// Without a catalog message, the built-in English or Persian text is used, in
+// English for the other languages.
+func (t *translation) FormatRelativeTime(value time.Time, languages ...string) string {
+	tag := t.formatLanguage(languages...)
+	diff := value.Sub(now())
+
+	direction := "future"
+	if diff < 0 {
+		direction, diff = "past", -diff
+	}
+
+	config := func(tag language.Tag) *i18n.LocalizeConfig {
+		for _, unit := range relativeUnits {
+			if count := int(diff / unit.size); count > 0 {
+				return &i18n.LocalizeConfig{
+					MessageID:    "time.relative." + unit.name + "." + direction,
+					TemplateData: map[string]interface{}{CountKey: t.FormatNumber(count, tag.String())},
+					PluralCount:  count,
+				}
+			}
+		}
+
+		return &i18n.LocalizeConfig{MessageID: "time.relative.now"}
+	}
+
+	override := config(tag)
+	if _, ok := t.snapshot().messages[tag][override.MessageID]; ok {
+		if message, err := t.lookup(override, tag.String()); err == nil {
+			return message
+		}
+	}
+
+	builtin := relativeTimeLanguage(tag)
+	message, err := i18n.NewLocalizer(relativeTimeBundle, builtin.String()).Localize(config(builtin))
+	if err != nil {
+		t.logger().Error("translation: failed to format relative time", "error", err)
+	}
+
+	return message
+}
+
+// toJalali converts a Gregorian date to the Jalali calendar.
+func toJalali(gy, gm, gd int) (jy, jm, jd int) {
+	daysBeforeMonth := [12]int{0, 31, 59, 90, 120, 151, 181, 212, 243, 273, 304, 334}
+
+	gy2 := gy
+	if gm > 2 {
+		gy2 = gy + 1
+	}
+
+	days := 355666 + 365*gy + (gy2+3)/4 - (gy2+99)/100 + (gy2+399)/400 + gd + daysBeforeMonth[gm-1]
+	jy = -1595 + 33*(days/12053)
+	days %= 12053
+	jy += 4 * (days / 1461)
+	days %= 1461
+
+	if days > 365 {
+		jy += (days - 1) / 365
+		days = (days - 1) % 365
+	}
+
+	if days < 186 {
+		return jy, 1 + days/31, 1 + days%31
+	}
+
+	return jy, 7 + (days-186)/30, 1 + (days-186)%30
+}
@@ -0,0 +1,102 @@
+package translation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// formatFiles are the locale files of the formatting tests.
+var formatFiles = map[string]string{
+	"fa.json": `{
+		"invoice.total": "مبلغ کل: {{currency .amount \"IRR\"}}",
+		"invoice.due": "سررسید {{date .due}}، {{relativeTime .due}}"
+	}`,
+	"en.json": `{
+		"invoice.total": "Total: {{currency .amount \"USD\"}} ({{percent .discount}} off)",
+		"time.relative.day.past": {"one": "yesterday", "other": "{{.count}} days back"}
+	}`,
+	"de.json": `{"invoice.total": "Summe: {{number .amount}}"}`,
+}
+
+func withNow(t *testing.T, value time.Time) {
+	previous := now
+	now = func() time.Time { return value }
+	t.Cleanup(func() { now = previous })
+}
+
+func TestTranslation_FormatNumber(t *testing.T) {
+	trans := newTranslationStub(t, Config{Locale: "fa"}, formatFiles)
+
+	assert.Equal(t, "۱٬۲۳۴٬۵۶۷٫۸۹", trans.FormatNumber(1234567.89))
+	assert.Equal(t, "۱٬۲۳۴", trans.FormatNumber(1234, "fa-IR"))
+	assert.Equal(t, "1,234,567.89", trans.FormatNumber(1234567.89, "en"))
+	assert.Equal(t, "۲۵٪", trans.FormatPercent(0.25))
+	assert.Equal(t, "25%", trans.FormatPercent(0.25, "en"))
+}
+
+func TestTranslation_FormatCurrency(t *testing.T) {
+	trans := newTranslationStub(t, Config{Locale: "fa"}, formatFiles)
+
+	assert.Equal(t, "ریال ۱٬۲۳۴٬۵۰۰", trans.FormatCurrency(1234500, "IRR"))
+	assert.Equal(t, "ریال ۱٬۰۰۰", trans.FormatCurrency(1000, ""))
+	assert.Equal(t, "$ 12.50", trans.FormatCurrency(12.5, "USD", "en"))
+	assert.Equal(t, "12.5", trans.FormatCurrency(12.5, "invalid", "en"))
+}
+
+func TestTranslation_FormatDate(t *testing.T) {
+	trans := newTranslationStub(t, Config{Locale: "fa"}, formatFiles)
+	nowruz := time.Date(2024, time.March, 20, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "۱ فروردین ۱۴۰۳", trans.FormatDate(nowruz))
+	assert.Equal(t, "۱۰ دی ۱۴۰۲", trans.FormatDate(time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "March 20, 2024", trans.FormatDate(nowruz, "en"))
+	assert.Equal(t, "2024-03-20", trans.FormatDate(nowruz, "de"))
+}
+
+func TestTranslation_FormatRelativeTime(t *testing.T) {
+	reference := time.Date(2024, time.March, 20, 12, 0, 0, 0, time.UTC)
+	withNow(t, reference)
+	trans := newTranslationStub(t, Config{Locale: "fa"}, formatFiles)
+
+	assert.Equal(t, "۳ روز پیش", trans.FormatRelativeTime(reference.Add(-72*time.Hour)))
+	assert.Equal(t, "۲ ساعت بعد", trans.FormatRelativeTime(reference.Add(2*time.Hour+time.Minute)))
+	assert.Equal(t, "اکنون", trans.FormatRelativeTime(reference))
+	assert.Equal(t, "in 1 minute", trans.FormatRelativeTime(reference.Add(time.Minute), "en"))
+	assert.Equal(t, "5 years ago", trans.FormatRelativeTime(reference.AddDate(-5, 0, -2), "en"))
+	assert.Equal(t, "yesterday", trans.FormatRelativeTime(reference.Add(-30*time.Hour), "en"))
+	assert.Equal(t, "3 days back", trans.FormatRelativeTime(reference.Add(-72*time.Hour), "en"))
+	assert.Equal(t, "۳ روز پیش", trans.FormatRelativeTime(reference.Add(-72*time.Hour), "fa-IR"))
+
+	// Arabic has no built-in messages and gets English rather than Persian.
+	trans = newTranslationStub(t, Config{Locale: "ar"}, map[string]string{"ar.json": `{"greeting": "مرحبا"}`})
+	assert.Equal(t, "3 days ago", trans.FormatRelativeTime(reference.Add(-72*time.Hour)))
+}
+
+func TestTranslation_TemplateFuncs(t *testing.T) {
+	reference := time.Date(2024, time.March, 20, 12, 0, 0, 0, time.UTC)
+	withNow(t, reference)
+	trans := newTranslationStub(t, Config{Locale: "fa"}, formatFiles)
+
+	assert.Equal(t, "مبلغ کل: ریال ۲۵۰٬۰۰۰", trans.Trans("invoice.total", map[string]interface{}{"amount": 250000}))
+	assert.Equal(t, "Total: $ 19.99 (10% off)", trans.Trans("invoice.total", map[string]interface{}{"amount": 19.99, "discount": 0.1}, "en"))
+	assert.Equal(t, "سررسید ۲ فروردین ۱۴۰۳، ۱ روز بعد", trans.Trans("invoice.due", map[string]interface{}{"due": reference.Add(24 * time.Hour)}))
+}
+
+func TestToJalali(t *testing.T) {
+	tests := []struct {
+		gregorian [3]int
+		jalali    [3]int
+	}{
+		{gregorian: [3]int{2024, 3, 20}, jalali: [3]int{1403, 1, 1}},
+		{gregorian: [3]int{2025, 3, 20}, jalali: [3]int{1403, 12, 30}},
+		{gregorian: [3]int{1979, 2, 11}, jalali: [3]int{1357, 11, 22}},
+		{gregorian: [3]int{2000, 1, 1}, jalali: [3]int{1378, 10, 11}},
+	}
+
+	for _, tt := range tests {
+		y, m, d := toJalali(tt.gregorian[0], tt.gregorian[1], tt.gregorian[2])
+		assert.Equal(t, tt.jalali, [3]int{y, m, d})
+	}
+}
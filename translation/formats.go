@@ -0,0 +1,27 @@
+package translation
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// unmarshalFuncs maps the extension of a message file, without the dot,
+// to the function used to decode it.
+var unmarshalFuncs = map[string]i18n.UnmarshalFunc{
+	"json": json.Unmarshal,
+	"yaml": yaml.Unmarshal,
+	"yml":  yaml.Unmarshal,
+	"toml": toml.Unmarshal,
+	"po":   unmarshalPO,
+}
+
+// isMessageFile reports whether path has the extension of a supported message file format.
+func isMessageFile(path string) bool {
+	_, ok := unmarshalFuncs[strings.TrimPrefix(filepath.Ext(path), ".")]
+	return ok
+}
@@ -0,0 +1,476 @@
+package translation
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n/template"
+	"golang.org/x/text/feature/plural"
+)
+
+// choicePattern matches the start of an ICU MessageFormat choice, such as
+// {gender, select, or {count, plural,.
+var choicePattern = regexp.MustCompile(`\{\s*([A-Za-z_]\w*)\s*,\s*(select|plural|selectordinal)\s*,`)
+
+// pluralForms are the names of the CLDR plural forms in ICU messages.
+var pluralForms = map[plural.Form]string{
+	plural.Other: "other",
+	plural.Zero:  "zero",
+	plural.One:   "one",
+	plural.Two:   "two",
+	plural.Few:   "few",
+	plural.Many:  "many",
+}
+
+// choice is an ICU select, plural or selectordinal argument of a message.
+// offset is its byte offset in the message and pluralOffset the offset:N of a
+// plural, subtracted from the argument before selecting a plural form and
+// formatting #.
+type choice struct {
+	argument     string
+	kind         string
+	offset       int
+	pluralOffset int
+	cases        map[string][]interface{}
+}
+
+// has reports whether c has the case key.
+func (c *choice) has(key string) bool {
+	_, ok := c.cases[key]
+	return ok
+}
+
+// hash is the # of a plural case, replaced by the formatted number.
+type hash struct{}
+
+// choiceTemplate is a message with ICU choices. The choices are resolved on
+// execution, then the text of the selected cases is executed as templates, so
+// a case may still use arguments such as {{.name}}.
+type choiceTemplate struct {
+	parser     *templateParser
+	nodes      []interface{}
+	leftDelim  string
+	rightDelim string
+}
+
+// isChoice reports whether s starts with an ICU choice.
+func isChoice(s string) bool {
+	match := choicePattern.FindStringIndex(s)
+	return match != nil && match[0] == 0
+}
+
+// hasChoice reports whether src uses ICU choices.
+func hasChoice(src string) bool {
+	return strings.Contains(src, ",") && choicePattern.MatchString(src)
+}
+
+// MessageArgument is an argument an ICU choice of a message selects on.
+type MessageArgument struct {
+	// Name is the argument, e.g. gender in {gender, select, male {He} other {They}}.
+	Name string
+	// Kind is the kind of the choice: select, plural or selectordinal.
+	Kind string
+	// Offset is the byte offset of the choice in the message.
+	Offset int
+}
+
+// MessageArguments returns the arguments the ICU choices of a message select
+// on, in order of appearance. An argument used by several choices is returned
+// once, with its first choice.
+func MessageArguments(src string) ([]MessageArgument, error) {
+	if !hasChoice(src) {
+		return nil, nil
+	}
+
+	nodes, err := (&choiceParser{src: src, leftDelim: "{{", rightDelim: "}}"}).parse()
+	if err != nil {
+		return nil, err
+	}
+
+	var arguments []MessageArgument
+	var walk func(nodes []interface{})
+	walk = func(nodes []interface{}) {
+		for _, node := range nodes {
+			c, ok := node.(*choice)
+			if !ok {
+				continue
+			}
+			arguments = append(arguments, MessageArgument{Name: c.argument, Kind: c.kind, Offset: c.offset})
+			for _, nodes := range c.cases {
+				walk(nodes)
+			}
+		}
+	}
+	walk(nodes)
+
+	sort.Slice(arguments, func(i, j int) bool {
+		return arguments[i].Offset < arguments[j].Offset
+	})
+
+	unique := arguments[:0]
+	seen := make(map[string]bool)
+	for _, argument := range arguments {
+		if !seen[argument.Name] {
+			seen[argument.Name] = true
+			unique = append(unique, argument)
+		}
+	}
+
+	return unique, nil
+}
+
+func (c *choiceTemplate) Execute(data any) (string, error) {
+	var b strings.Builder
+	if err := c.execute(&b, c.nodes, data, ""); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// execute writes nodes to b, number being the formatted value of the closest
+// enclosing plural.
+func (c *choiceTemplate) execute(b *strings.Builder, nodes []interface{}, data any, number string) error {
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case string:
+			parsed, err := c.parser.parseTemplate(n, c.leftDelim, c.rightDelim)
+			if err != nil {
+				return err
+			}
+			text, err := parsed.Execute(data)
+			if err != nil {
+				return err
+			}
+			b.WriteString(text)
+		case hash:
+			b.WriteString(number)
+		case *choice:
+			value, ok := argument(data, n.argument)
+			if !ok {
+				return fmt.Errorf("translation: missing argument %q", n.argument)
+			}
+
+			key, err := c.parser.choose(n, value)
+			if err != nil {
+				return err
+			}
+
+			if n.kind != "select" {
+				number = c.parser.number(withoutOffset(n, value))
+			}
+			if err := c.execute(b, n.cases[key], data, number); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// choose returns the case of c selected by value.
+func (p *templateParser) choose(c *choice, value interface{}) (string, error) {
+	if c.kind == "select" {
+		if key := fmt.Sprint(value); c.has(key) {
+			return key, nil
+		}
+		return "other", nil
+	}
+
+	n, err := strconv.ParseFloat(numberString(value), 64)
+	if err != nil {
+		return "", fmt.Errorf("translation: argument %q of %s is not a number: %v", c.argument, c.kind, value)
+	}
+
+	if key := "=" + strconv.FormatFloat(n, 'f', -1, 64); c.has(key) {
+		return key, nil
+	}
+
+	rules := plural.Cardinal
+	if c.kind == "selectordinal" {
+		rules = plural.Ordinal
+	}
+
+	i, v, w, f, t := operands(numberString(withoutOffset(c, value)))
+	if key := pluralForms[rules.MatchPlural(p.tag, i, v, w, f, t)]; c.has(key) {
+		return key, nil
+	}
+
+	return "other", nil
+}
+
+// withoutOffset returns value less the plural offset of c, as an int when it
+// is an integer and as a float64 otherwise.
+func withoutOffset(c *choice, value interface{}) interface{} {
+	if c.pluralOffset == 0 {
+		return value
+	}
+
+	s := numberString(value)
+	if n, err := strconv.Atoi(s); err == nil {
+		return n - c.pluralOffset
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return value
+	}
+
+	return n - float64(c.pluralOffset)
+}
+
+// numberString returns the decimal representation of value, keeping the
+// trailing zeros of a string such as "1.50" since they select plural forms.
+func numberString(value interface{}) string {
+	switch v := value.(type) {
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
+
+// operands returns the CLDR plural operands of the decimal number s.
+func operands(s string) (i, v, w, f, t int) {
+	s = strings.TrimPrefix(s, "-")
+	integer, fraction, _ := strings.Cut(s, ".")
+	trimmed := strings.TrimRight(fraction, "0")
+
+	// Operands too large for an int may be passed modulo 10,000,000.
+	mod := func(digits string) int {
+		if len(digits) > 7 {
+			digits = digits[len(digits)-7:]
+		}
+		n, _ := strconv.Atoi(digits)
+		return n
+	}
+
+	return mod(integer), len(fraction), len(trimmed), mod(fraction), mod(trimmed)
+}
+
+// argument returns the value of name in the template data, a map or a struct.
+func argument(data any, name string) (interface{}, bool) {
+	if m, ok := data.(map[string]interface{}); ok {
+		value, ok := m[name]
+		return value, ok
+	}
+
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		if value := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key())); value.IsValid() {
+			return value.Interface(), true
+		}
+	case reflect.Struct:
+		if value := v.FieldByName(name); value.IsValid() && value.CanInterface() {
+			return value.Interface(), true
+		}
+	}
+
+	return nil, false
+}
+
+// choiceParser parses a message with ICU choices. The template actions of
+// the message, such as {{.name}}, are kept as text.
+type choiceParser struct {
+	src        string
+	pos        int
+	leftDelim  string
+	rightDelim string
+}
+
+func (p *choiceParser) parse() ([]interface{}, error) {
+	return p.nodes(false, false)
+}
+
+// nodes parses text and choices until the end of the message, or of the case
+// when inCase is set. # is the number of the plural when inPlural is set.
+func (p *choiceParser) nodes(inCase, inPlural bool) ([]interface{}, error) {
+	var (
+		nodes []interface{}
+		text  strings.Builder
+	)
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, text.String())
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.src) {
+		rest := p.src[p.pos:]
+
+		switch {
+		case strings.HasPrefix(rest, p.leftDelim):
+			end := strings.Index(rest[len(p.leftDelim):], p.rightDelim)
+			if end < 0 {
+				return nil, p.errorf("unclosed action")
+			}
+			end += len(p.leftDelim) + len(p.rightDelim)
+			text.WriteString(rest[:end])
+			p.pos += end
+		case isChoice(rest):
+			flush()
+			c, err := p.choice()
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, c)
+		case rest[0] == '}' && inCase:
+			flush()
+			return nodes, nil
+		case rest[0] == '#' && inPlural:
+			flush()
+			nodes = append(nodes, hash{})
+			p.pos++
+		case rest[0] == '\'':
+			text.WriteString(p.quoted())
+		default:
+			text.WriteByte(rest[0])
+			p.pos++
+		}
+	}
+
+	if inCase {
+		return nil, p.errorf("unclosed case")
+	}
+	flush()
+
+	return nodes, nil
+}
+
+// quoted parses an apostrophe: a doubled apostrophe is a literal apostrophe
+// and an apostrophe before a syntax character, as in '{...', quotes the text
+// up to the next apostrophe. Any other apostrophe is literal.
+func (p *choiceParser) quoted() string {
+	rest := p.src[p.pos:]
+	if len(rest) < 2 || !strings.ContainsRune("'{}#", rune(rest[1])) {
+		p.pos++
+		return "'"
+	}
+
+	if rest[1] == '\'' {
+		p.pos += 2
+		return "'"
+	}
+
+	end := strings.IndexByte(rest[1:], '\'')
+	if end < 0 {
+		p.pos = len(p.src)
+		return rest[1:]
+	}
+	p.pos += end + 2
+
+	return rest[1 : end+1]
+}
+
+// choice parses {argument, kind, key {case} ...}, with an offset:N before
+// the cases of a plural.
+func (p *choiceParser) choice() (*choice, error) {
+	match := choicePattern.FindStringSubmatchIndex(p.src[p.pos:])
+	c := &choice{
+		argument: p.src[p.pos+match[2] : p.pos+match[3]],
+		kind:     p.src[p.pos+match[4] : p.pos+match[5]],
+		offset:   p.pos,
+		cases:    make(map[string][]interface{}),
+	}
+	p.pos += match[1]
+
+	p.skipSpaces()
+	if c.kind != "select" && strings.HasPrefix(p.src[p.pos:], "offset:") {
+		p.pos += len("offset:")
+		p.skipSpaces()
+
+		start := p.pos
+		for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+			p.pos++
+		}
+
+		offset, err := strconv.Atoi(p.src[start:p.pos])
+		if err != nil {
+			return nil, p.errorf("invalid offset of %q", c.argument)
+		}
+		c.pluralOffset = offset
+	}
+
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unclosed %s of %q", c.kind, c.argument)
+		}
+
+		if p.src[p.pos] == '}' {
+			p.pos++
+			break
+		}
+
+		start := p.pos
+		for p.pos < len(p.src) && !strings.ContainsRune(" \t\n{}", rune(p.src[p.pos])) {
+			p.pos++
+		}
+		key := p.src[start:p.pos]
+
+		p.skipSpaces()
+		if key == "" || p.pos >= len(p.src) || p.src[p.pos] != '{' {
+			return nil, p.errorf("expected a case of %q", c.argument)
+		}
+		p.pos++
+
+		nodes, err := p.nodes(true, c.kind != "select")
+		if err != nil {
+			return nil, err
+		}
+		p.pos++
+		c.cases[key] = nodes
+	}
+
+	if !c.has("other") {
+		return nil, p.errorf("%s of %q has no other case", c.kind, c.argument)
+	}
+
+	return c, nil
+}
+
+func (p *choiceParser) skipSpaces() {
+	for p.pos < len(p.src) && strings.ContainsRune(" \t\n\r", rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *choiceParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("translation: invalid message at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+// parseChoices parses a message with ICU choices.
+func (p *templateParser) parseChoices(src, leftDelim, rightDelim string) (template.ParsedTemplate, error) {
+	if leftDelim == "" {
+		leftDelim = "{{"
+	}
+	if rightDelim == "" {
+		rightDelim = "}}"
+	}
+
+	parser := &choiceParser{src: src, leftDelim: leftDelim, rightDelim: rightDelim}
+	nodes, err := parser.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return &choiceTemplate{parser: p, nodes: nodes, leftDelim: leftDelim, rightDelim: rightDelim}, nil
+}
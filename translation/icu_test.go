@@ -0,0 +1,114 @@
+package translation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// choiceFiles are the locale files of the ICU choice tests.
+var choiceFiles = map[string]string{
+	"en.json": `{
+		"post.liked": "{gender, select, male {He} female {She} other {They}} liked {{.post}}",
+		"inbox": "{count, plural, =0 {No messages} one {# message} other {# messages}} for {{.name}}",
+		"race.place": "{place, selectordinal, one {#st} two {#nd} few {#rd} other {#th}} place",
+		"party": "{host, select, female {{guests, plural, one {She invited # guest} other {She invited # guests}}} other {{guests, plural, one {They invited # guest} other {They invited # guests}}}}",
+		"quoted": "It''s '{count, plural, one {#}}' as is",
+		"likes": "{count, plural, offset:1 =0 {Nobody liked this} =1 {{{.name}} liked this} one {{{.name}} and # other liked this} other {{{.name}} and # others liked this}}",
+		"invalid": "{gender, select, male {He}}"
+	}`,
+	"fa.json": `{
+		"post.liked": "{gender, select, male {او} female {او} other {آن‌ها}} {{.post}} را پسندید",
+		"inbox": "{count, plural, =0 {پیامی ندارید} other {# پیام دارید}}"
+	}`,
+	"ar.json": `{
+		"inbox": "{count, plural, zero {لا رسائل} one {رسالة واحدة} two {رسالتان} few {# رسائل} many {# رسالة} other {# رسالة}}"
+	}`,
+}
+
+func TestTranslation_Select(t *testing.T) {
+	trans := newTranslationStub(t, Config{Locale: "en", Logger: NopLogger}, choiceFiles)
+
+	assert.Equal(t, "She liked the photo", trans.Trans("post.liked", map[string]interface{}{"gender": "female", "post": "the photo"}))
+	assert.Equal(t, "He liked the photo", trans.Trans("post.liked", map[string]interface{}{"gender": "male", "post": "the photo"}))
+	assert.Equal(t, "They liked the photo", trans.Trans("post.liked", map[string]interface{}{"gender": "nonbinary", "post": "the photo"}))
+	assert.Equal(t, "آن‌ها عکس را پسندید", trans.Trans("post.liked", map[string]interface{}{"gender": "", "post": "عکس"}, "fa"))
+}
+
+func TestTranslation_Plural(t *testing.T) {
+	trans := newTranslationStub(t, Config{Locale: "en", Logger: NopLogger}, choiceFiles)
+
+	assert.Equal(t, "No messages for Sara", trans.Trans("inbox", map[string]interface{}{"count": 0, "name": "Sara"}))
+	assert.Equal(t, "1 message for Sara", trans.Trans("inbox", map[string]interface{}{"count": 1, "name": "Sara"}))
+	assert.Equal(t, "1,200 messages for Sara", trans.Trans("inbox", map[string]interface{}{"count": 1200, "name": "Sara"}))
+	assert.Equal(t, "1.5 messages for Sara", trans.Trans("inbox", map[string]interface{}{"count": 1.5, "name": "Sara"}))
+	assert.Equal(t, "پیامی ندارید", trans.Trans("inbox", map[string]interface{}{"count": 0}, "fa"))
+	assert.Equal(t, "۱٬۲۰۰ پیام دارید", trans.Trans("inbox", map[string]interface{}{"count": 1200}, "fa"))
+
+	tests := map[int]string{
+		0:   "لا رسائل",
+		1:   "رسالة واحدة",
+		2:   "رسالتان",
+		3:   "٣ رسائل",
+		11:  "١١ رسالة",
+		100: "١٠٠ رسالة",
+	}
+	for count, want := range tests {
+		assert.Equal(t, want, trans.Trans("inbox", map[string]interface{}{"count": count}, "ar"))
+	}
+}
+
+func TestTranslation_PluralOffset(t *testing.T) {
+	trans := newTranslationStub(t, Config{Locale: "en", Logger: NopLogger}, choiceFiles)
+
+	tests := map[int]string{
+		0: "Nobody liked this",
+		1: "Sara liked this",
+		2: "Sara and 1 other liked this",
+		5: "Sara and 4 others liked this",
+	}
+	for count, want := range tests {
+		assert.Equal(t, want, trans.Trans("likes", map[string]interface{}{"count": count, "name": "Sara"}))
+	}
+}
+
+func TestTranslation_SelectOrdinal(t *testing.T) {
+	trans := newTranslationStub(t, Config{Locale: "en", Logger: NopLogger}, choiceFiles)
+
+	tests := map[int]string{1: "1st place", 2: "2nd place", 3: "3rd place", 4: "4th place", 11: "11th place", 22: "22nd place", 103: "103rd place"}
+	for place, want := range tests {
+		assert.Equal(t, want, trans.Trans("race.place", map[string]interface{}{"place": place}))
+	}
+}
+
+func TestTranslation_NestedChoices(t *testing.T) {
+	trans := newTranslationStub(t, Config{Locale: "en", Logger: NopLogger}, choiceFiles)
+
+	assert.Equal(t, "She invited 1 guest", trans.Trans("party", map[string]interface{}{"host": "female", "guests": 1}))
+	assert.Equal(t, "They invited 3 guests", trans.Trans("party", map[string]interface{}{"host": "male", "guests": 3}))
+}
+
+func TestTranslation_InvalidChoices(t *testing.T) {
+	trans := newTranslationStub(t, Config{Locale: "en", Logger: NopLogger}, choiceFiles)
+
+	assert.Equal(t, "It's {count, plural, one {#}} as is", trans.Trans("quoted", nil))
+	assert.Equal(t, "invalid", trans.Trans("invalid", map[string]interface{}{"gender": "male"}))
+	assert.Equal(t, "inbox", trans.Trans("inbox", map[string]interface{}{"count": "many"}))
+	assert.Equal(t, "inbox", trans.Trans("inbox", nil))
+}
+
+func TestMessageArguments(t *testing.T) {
+	arguments, err := MessageArguments("{host, select, female {{guests, plural, other {#}}} other {{{.name}}}}")
+	assert.NoError(t, err)
+	assert.Equal(t, []MessageArgument{
+		{Name: "host", Kind: "select", Offset: 0},
+		{Name: "guests", Kind: "plural", Offset: 23},
+	}, arguments)
+
+	arguments, err = MessageArguments("Hello {{.name}}")
+	assert.NoError(t, err)
+	assert.Empty(t, arguments)
+
+	_, err = MessageArguments("{gender, select, male {He}")
+	assert.Error(t, err)
+}
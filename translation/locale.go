@@ -0,0 +1,72 @@
+package translation
+
+import (
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// Direction is the direction text of a locale is written in.
+type Direction string
+
+const (
+	LeftToRight Direction = "ltr"
+	RightToLeft Direction = "rtl"
+)
+
+// rightToLeftScripts are the ISO 15924 codes of the scripts written right to left.
+var rightToLeftScripts = map[string]bool{
+	"Adlm": true, "Arab": true, "Hebr": true, "Mand": true, "Mend": true, "Nkoo": true,
+	"Rohg": true, "Samr": true, "Syrc": true, "Thaa": true,
+}
+
+// Locale describes a locale.
+type Locale struct {
+	// Tag is the language of the locale.
+	Tag language.Tag
+	// Direction is the direction text of the locale is written in.
+	Direction Direction
+	// Name is the name of the locale in its own language, e.g. فارسی.
+	Name string
+	// EnglishName is the name of the locale in English, e.g. Persian.
+	EnglishName string
+	// Keys is the number of messages loaded for the locale itself, without its fallbacks.
+	Keys int
+}
+
+// Locale returns the metadata of the locale messages are resolved in for the
+// requested language, i.e. the first loaded language of its fallback chain.
+func (t *translation) Locale(languages ...string) Locale {
+	return t.locale(t.formatLanguage(languages...))
+}
+
+// Locales returns the metadata of the loaded locales, sorted by tag.
+func (t *translation) Locales() []Locale {
+	tags := t.Languages()
+	locales := make([]Locale, 0, len(tags))
+	for _, tag := range tags {
+		locales = append(locales, t.locale(tag))
+	}
+
+	return locales
+}
+
+// locale returns the metadata of tag.
+func (t *translation) locale(tag language.Tag) Locale {
+	return Locale{
+		Tag:         tag,
+		Direction:   direction(tag),
+		Name:        display.Self.Name(tag),
+		EnglishName: display.English.Tags().Name(tag),
+		Keys:        len(t.snapshot().messages[tag]),
+	}
+}
+
+// direction returns the direction of the script of tag, guessing the script
+// from the language when the tag has none.
+func direction(tag language.Tag) Direction {
+	if script, _ := tag.Script(); rightToLeftScripts[script.String()] {
+		return RightToLeft
+	}
+
+	return LeftToRight
+}
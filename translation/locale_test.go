@@ -0,0 +1,54 @@
+package translation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestTranslation_Locale(t *testing.T) {
+	trans := newTranslationStub(t, Config{Locale: "fa"}, map[string]string{
+		"fa.json": `{"greeting": "سلام", "farewell": "خداحافظ"}`,
+		"en.json": `{"greeting": "Hello"}`,
+		"ar.json": `{"greeting": "مرحبا"}`,
+	})
+
+	assert.Equal(t, Locale{
+		Tag:         language.Persian,
+		Direction:   RightToLeft,
+		Name:        "فارسی",
+		EnglishName: "Persian",
+		Keys:        2,
+	}, trans.Locale())
+	assert.Equal(t, Locale{
+		Tag:         language.English,
+		Direction:   LeftToRight,
+		Name:        "English",
+		EnglishName: "English",
+		Keys:        1,
+	}, trans.Locale("en-GB"))
+	assert.Equal(t, language.English, trans.Locale("de").Tag)
+
+	locales := trans.Locales()
+	assert.Len(t, locales, 3)
+	assert.Equal(t, language.Arabic, locales[0].Tag)
+	assert.Equal(t, RightToLeft, locales[0].Direction)
+}
+
+func TestDirection(t *testing.T) {
+	tests := map[string]Direction{
+		"fa":      RightToLeft,
+		"ar-EG":   RightToLeft,
+		"he":      RightToLeft,
+		"ur":      RightToLeft,
+		"az-Arab": RightToLeft,
+		"az":      LeftToRight,
+		"en":      LeftToRight,
+		"tr":      LeftToRight,
+	}
+
+	for lang, want := range tests {
+		assert.Equal(t, want, direction(language.MustParse(lang)), lang)
+	}
+}
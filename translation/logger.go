@@ -0,0 +1,32 @@
+package translation
+
+import (
+	"log/slog"
+)
+
+// Logger receives the events of the package. It is satisfied by *slog.Logger.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// NopLogger is a Logger that discards every event, e.g. to silence tests.
+var NopLogger Logger = nopLogger{}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any) {}
+func (nopLogger) Info(string, ...any)  {}
+func (nopLogger) Warn(string, ...any)  {}
+func (nopLogger) Error(string, ...any) {}
+
+// logger returns Config.Logger, or slog.Default if none is configured.
+func (t *translation) logger() Logger {
+	if t.config.Logger != nil {
+		return t.config.Logger
+	}
+
+	return slog.Default()
+}
@@ -0,0 +1,30 @@
+package translation
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Logger(t *testing.T) {
+	var buf bytes.Buffer
+	trans := NewTranslation(Config{
+		Locale: "en",
+		Logger: slog.New(slog.NewTextHandler(&buf, nil)),
+	})
+
+	trans.Trans("unknown", nil, "fa")
+
+	assert.Contains(t, buf.String(), "level=WARN")
+	assert.Contains(t, buf.String(), `msg="translation: message is missing" language=fa key=unknown`)
+}
+
+func TestNopLogger(t *testing.T) {
+	trans := NewTranslation(Config{Locale: "en", Logger: NopLogger})
+
+	assert.NotPanics(t, func() {
+		trans.Trans("unknown", nil, "not a language")
+	})
+}
@@ -0,0 +1,61 @@
+package translation
+
+import (
+	"sort"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+// Languages returns the languages messages are loaded for, sorted.
+func (t *translation) Languages() []language.Tag {
+	loaded := t.snapshot().messages
+	tags := make([]language.Tag, 0, len(loaded))
+	for tag := range loaded {
+		tags = append(tags, tag)
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].String() < tags[j].String()
+	})
+
+	return tags
+}
+
+// Messages returns the messages loaded for lang, sorted by ID. Only the
+// catalogs of lang itself are considered, not its fallbacks.
+func (t *translation) Messages(lang string) []*i18n.Message {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return nil
+	}
+
+	loaded := t.snapshot().messages[tag]
+	messages := make([]*i18n.Message, 0, len(loaded))
+	for _, message := range loaded {
+		messages = append(messages, message)
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].ID < messages[j].ID
+	})
+
+	return messages
+}
+
+// Catalog returns the messages a request in the given language is served with:
+// the messages of every loaded language of its fallback chain, the most
+// specific language winning, keyed by ID.
+func (t *translation) Catalog(languages ...string) map[string]*i18n.Message {
+	current := t.snapshot()
+	chain := t.loadedChain(current, t.requestedLanguage(languages...))
+
+	catalog := make(map[string]*i18n.Message)
+	for i := len(chain) - 1; i >= 0; i-- {
+		for id, message := range current.messages[chain[i]] {
+			catalog[id] = message
+		}
+	}
+
+	return catalog
+}
@@ -0,0 +1,63 @@
+package translation
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// LanguageKey is the gin context key the negotiated language is stored under.
+	LanguageKey = "gokit.translation.language"
+
+	defaultLanguageQueryParam = "lang"
+	defaultLanguageCookieName = "lang"
+)
+
+type MiddlewareConfig struct {
+	// QueryParam is the query parameter that selects a language. Defaults to "lang".
+	QueryParam string
+	// CookieName is the cookie that selects a language. Defaults to "lang".
+	CookieName string
+}
+
+// Middleware negotiates the language of every request and stores it on the gin
+// context under LanguageKey and on the request context, see
+// LanguageFromContext. The query parameter takes precedence over the cookie,
+// which takes precedence over the Accept-Language header; candidates are
+// matched against the languages loaded in t.
+func Middleware(t Translation, configs ...MiddlewareConfig) gin.HandlerFunc {
+	config := MiddlewareConfig{}
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+
+	if config.QueryParam == "" {
+		config.QueryParam = defaultLanguageQueryParam
+	}
+
+	if config.CookieName == "" {
+		config.CookieName = defaultLanguageCookieName
+	}
+
+	return func(ctx *gin.Context) {
+		cookie, _ := ctx.Cookie(config.CookieName)
+
+		tag := t.Negotiate(
+			ctx.Query(config.QueryParam),
+			cookie,
+			ctx.GetHeader("Accept-Language"),
+		)
+
+		ctx.Set(LanguageKey, tag.String())
+		ctx.Request = ctx.Request.WithContext(WithLanguage(ctx.Request.Context(), tag.String()))
+		ctx.Next()
+	}
+}
+
+// GetLanguage returns the language negotiated by Middleware, or an empty string.
+func GetLanguage(ctx *gin.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	return ctx.GetString(LanguageKey)
+}
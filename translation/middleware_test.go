@@ -0,0 +1,67 @@
+package translation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// negotiationFiles are the locale files of the negotiation tests.
+var negotiationFiles = map[string]string{
+	"fa.json": `{"greeting": "سلام"}`,
+	"en.json": `{"greeting": "Hello"}`,
+	"de.json": `{"greeting": "Hallo"}`,
+}
+
+func TestTranslation_Negotiate(t *testing.T) {
+	trans := newTranslationStub(t, Config{Locale: "fa"}, negotiationFiles)
+
+	assert.Equal(t, "de", trans.Negotiate("de-AT").String())
+	assert.Equal(t, "en", trans.Negotiate("fr-FR,en;q=0.8,de;q=0.5").String())
+	assert.Equal(t, "de", trans.Negotiate("", "de", "en").String())
+	assert.Equal(t, "fa", trans.Negotiate("ja").String())
+	assert.Equal(t, "fa", trans.Negotiate().String())
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	trans := newTranslationStub(t, Config{Locale: "fa"}, negotiationFiles)
+
+	tests := []struct {
+		name   string
+		url    string
+		cookie string
+		header string
+		want   string
+	}{
+		{name: "accept language", url: "/", header: "de;q=0.9,en;q=0.5", want: "de"},
+		{name: "cookie over header", url: "/", cookie: "en", header: "de", want: "en"},
+		{name: "query over cookie", url: "/?lang=de", cookie: "en", want: "de"},
+		{name: "default", url: "/", header: "ja", want: "fa"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			router := gin.New()
+			router.Use(Middleware(trans))
+			router.GET("/", func(ctx *gin.Context) {
+				got = GetLanguage(ctx)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.header != "" {
+				req.Header.Set("Accept-Language", tt.header)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "lang", Value: tt.cookie})
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package translation
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MissingHandler is called when a message is missing in every language of the
+// fallback chain, with the requested language and the message ID.
+type MissingHandler func(lang, key string)
+
+// MissingTranslationError is the value strict mode panics with.
+type MissingTranslationError struct {
+	Language string
+	Key      string
+}
+
+func (e *MissingTranslationError) Error() string {
+	return fmt.Sprintf("translation: message %q is missing in language %q", e.Key, e.Language)
+}
+
+// missing reports a message that could not be resolved.
+func (t *translation) missing(lang, key string) {
+	if t.config.OnMissing != nil {
+		t.config.OnMissing(lang, key)
+	} else {
+		t.logger().Warn("translation: message is missing", "language", lang, "key", key)
+	}
+
+	if t.config.Strict {
+		panic(&MissingTranslationError{Language: lang, Key: key})
+	}
+}
+
+// MissingReport collects the messages reported missing. Its Handle method is
+// meant to be used as Config.OnMissing.
+type MissingReport struct {
+	mu   sync.Mutex
+	keys map[string]map[string]struct{}
+}
+
+// NewMissingReport creates an empty missing translation report.
+func NewMissingReport() *MissingReport {
+	return &MissingReport{
+		keys: make(map[string]map[string]struct{}),
+	}
+}
+
+// Handle records a missing message.
+func (r *MissingReport) Handle(lang, key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.keys[lang] == nil {
+		r.keys[lang] = make(map[string]struct{})
+	}
+	r.keys[lang][key] = struct{}{}
+}
+
+// Keys returns the sorted message IDs reported missing, grouped by language.
+func (r *MissingReport) Keys() map[string][]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := make(map[string][]string, len(r.keys))
+	for lang, set := range r.keys {
+		for key := range set {
+			keys[lang] = append(keys[lang], key)
+		}
+		sort.Strings(keys[lang])
+	}
+
+	return keys
+}
+
+// MissingKeys returns the sorted message IDs loaded for base but not for target,
+// so that incomplete catalogs can be detected.
+func MissingKeys(t Translation, base, target string) []string {
+	present := make(map[string]struct{})
+	for _, message := range t.Messages(target) {
+		present[message.ID] = struct{}{}
+	}
+
+	var keys []string
+	for _, message := range t.Messages(base) {
+		if _, ok := present[message.ID]; !ok {
+			keys = append(keys, message.ID)
+		}
+	}
+
+	return keys
+}
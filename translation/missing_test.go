@@ -0,0 +1,48 @@
+package translation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// missingFiles are the locale files of the missing message tests.
+var missingFiles = map[string]string{
+	"en.json": `{"greeting": "Hello", "farewell": "Bye", "thanks": "Thanks"}`,
+	"fa.json": `{"greeting": "سلام", "farewell": ""}`,
+}
+
+func TestTranslation_OnMissing(t *testing.T) {
+	report := NewMissingReport()
+	trans := newTranslationStub(t, Config{Locale: "en", OnMissing: report.Handle}, missingFiles)
+
+	assert.Equal(t, "unknown", trans.Trans("unknown", nil, "fa"))
+	assert.Equal(t, "unknown", trans.Trans("unknown", nil))
+	assert.Equal(t, "other", trans.Trans("other", nil))
+	assert.Equal(t, "Bye", trans.Trans("farewell", nil, "fa"))
+
+	assert.Equal(t, map[string][]string{
+		"fa": {"unknown"},
+		"en": {"other", "unknown"},
+	}, report.Keys())
+}
+
+func TestTranslation_Strict(t *testing.T) {
+	trans := newTranslationStub(t, Config{Locale: "en", Strict: true}, missingFiles)
+
+	assert.NotPanics(t, func() {
+		trans.Trans("greeting", nil)
+	})
+	assert.PanicsWithError(t, `translation: message "unknown" is missing in language "fa"`, func() {
+		trans.Trans("unknown", nil, "fa")
+	})
+}
+
+func TestMissingKeys(t *testing.T) {
+	trans := newTranslationStub(t, Config{Locale: "en"}, missingFiles)
+
+	assert.Equal(t, []string{"farewell", "thanks"}, MissingKeys(trans, "en", "fa"))
+	assert.Empty(t, MissingKeys(trans, "fa", "en"))
+	assert.Len(t, trans.Messages("en"), 3)
+	assert.Empty(t, trans.Messages("de"))
+}
@@ -0,0 +1,85 @@
+package translation
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+// messageDefinitions records the file defining every message of a locale
+// source, by language, to detect messages defined twice.
+type messageDefinitions map[language.Tag]map[string]string
+
+// add records the messages of file and returns an error for every message
+// already defined by another file.
+func (d messageDefinitions) add(file *i18n.MessageFile) []error {
+	if d[file.Tag] == nil {
+		d[file.Tag] = make(map[string]string)
+	}
+
+	var errs []error
+	for _, message := range file.Messages {
+		if other, ok := d[file.Tag][message.ID]; ok {
+			errs = append(errs, fmt.Errorf("%s: message %q is already defined in %s", file.Path, message.ID, other))
+			continue
+		}
+		d[file.Tag][message.ID] = file.Path
+	}
+
+	return errs
+}
+
+// namespaceMessages prefixes the message IDs of file with the namespace of its
+// directory, e.g. billing.invoices for billing/invoices/fa.json.
+func namespaceMessages(file *i18n.MessageFile, filePath string) {
+	dir := path.Dir(filePath)
+	if dir == "." {
+		return
+	}
+
+	prefix := strings.ReplaceAll(dir, "/", ".") + "."
+	for _, message := range file.Messages {
+		message.ID = prefix + message.ID
+	}
+}
+
+// namespace is a Translation whose keys are relative to a namespace.
+type namespace struct {
+	Translation
+	prefix string
+}
+
+// Namespace returns a Translation translating the keys of the namespace ns,
+// e.g. Namespace("billing").Trans("total", nil) translates billing.total. An
+// empty ns returns the Translation unchanged.
+func (t *translation) Namespace(ns string) Translation {
+	if ns == "" {
+		return t
+	}
+
+	return &namespace{Translation: t, prefix: ns + "."}
+}
+
+func (n *namespace) Namespace(ns string) Translation {
+	if ns == "" {
+		return n
+	}
+
+	return &namespace{Translation: n.Translation, prefix: n.prefix + ns + "."}
+}
+
+func (n *namespace) Trans(key string, args map[string]interface{}, languages ...string) string {
+	return n.Translation.Trans(n.prefix+key, args, languages...)
+}
+
+func (n *namespace) TransCtx(ctx context.Context, key string, args map[string]interface{}) string {
+	return n.Translation.TransCtx(ctx, n.prefix+key, args)
+}
+
+func (n *namespace) TransPlural(key string, count interface{}, args map[string]interface{}, languages ...string) string {
+	return n.Translation.TransPlural(n.prefix+key, count, args, languages...)
+}
@@ -0,0 +1,91 @@
+package translation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslation_Namespaces(t *testing.T) {
+	trans := newTranslationStub(t, Config{
+		Locale:     "fa",
+		Namespaces: true,
+	}, map[string]string{
+		"fa.json":                   `{"greeting": "سلام"}`,
+		"billing/fa.json":           `{"total": "مبلغ کل"}`,
+		"billing/en.json":           `{"total": "Total"}`,
+		"billing/invoices/fa.yaml":  "title: فاکتور\n",
+		"accounts/fa.json":          `{"total": "تعداد حساب‌ها"}`,
+		"accounts/settings/fa.json": `{"title": "تنظیمات"}`,
+	})
+
+	assert.Equal(t, "سلام", trans.Trans("greeting", nil))
+	assert.Equal(t, "مبلغ کل", trans.Trans("billing.total", nil))
+	assert.Equal(t, "تعداد حساب‌ها", trans.Trans("accounts.total", nil))
+	assert.Equal(t, "فاکتور", trans.Trans("billing.invoices.title", nil))
+
+	billing := trans.Namespace("billing")
+	assert.Equal(t, "مبلغ کل", billing.Trans("total", nil))
+	assert.Equal(t, "Total", billing.Trans("total", nil, "en"))
+	assert.Equal(t, "فاکتور", billing.Namespace("invoices").Trans("title", nil))
+	assert.Equal(t, "تنظیمات", trans.Namespace("accounts").Namespace("settings").Trans("title", nil))
+	assert.Equal(t, "billing.greeting", billing.Trans("greeting", nil))
+	assert.Equal(t, "مبلغ کل", billing.Namespace("").Trans("total", nil))
+}
+
+func TestTranslation_WithoutNamespaces(t *testing.T) {
+	trans := newTranslationStub(t, Config{Locale: "fa"}, map[string]string{
+		"billing/fa.json": `{"total": "مبلغ کل"}`,
+	})
+
+	assert.Equal(t, "مبلغ کل", trans.Trans("total", nil))
+	assert.Same(t, trans, trans.Namespace(""))
+}
+
+func TestTranslation_DuplicatesWithoutNamespaces(t *testing.T) {
+	trans := newTranslationStub(t, Config{
+		Locale: "fa",
+		Logger: NopLogger,
+	}, map[string]string{
+		"fa.json":       `{"greeting": "سلام"}`,
+		"users/fa.json": `{"greeting": "درود"}`,
+	})
+
+	assert.NoError(t, trans.Reload())
+	assert.Equal(t, "درود", trans.Trans("greeting", nil))
+}
+
+func TestTranslation_Collisions(t *testing.T) {
+	dir := t.TempDir()
+	trans := newTranslationStub(t, Config{Locale: "fa", Namespaces: true, PathLocale: dir}, map[string]string{
+		"fa.json":         `{"billing.total": "جمع"}`,
+		"billing/fa.json": `{"tax": "مالیات"}`,
+	})
+
+	assert.NoError(t, trans.Reload())
+
+	if err := os.WriteFile(filepath.Join(dir, "billing", "fa.json"), []byte(`{"tax": "مالیات", "total": "مبلغ کل"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := trans.Reload()
+	assert.ErrorContains(t, err, `message "billing.total" is already defined in`)
+	assert.Equal(t, "جمع", trans.Trans("billing.total", nil))
+}
+
+func TestTranslation_OverridesAreNotCollisions(t *testing.T) {
+	trans := newTranslationStub(t, Config{
+		Locale: "fa",
+		FS: fstest.MapFS{
+			"fa.json": {Data: []byte(`{"greeting": "سلام"}`)},
+		},
+	}, map[string]string{
+		"fa.json": `{"greeting": "درود"}`,
+	})
+
+	assert.NoError(t, trans.Reload())
+	assert.Equal(t, "درود", trans.Trans("greeting", nil))
+}
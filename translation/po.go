@@ -0,0 +1,317 @@
+package translation
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// poPluralSamples are the counts the plural expression of a PO file is
+// evaluated on to map its msgstr[n] indexes to CLDR plural forms.
+var poPluralSamples = func() []int {
+	samples := make([]int, 0, 1004)
+	for n := 0; n <= 1000; n++ {
+		samples = append(samples, n)
+	}
+	return append(samples, 10000, 100000, 1000000)
+}()
+
+// poHeader holds the fields of the header entry of a PO file used to read
+// plural entries.
+type poHeader struct {
+	language language.Tag
+	nplurals int
+	plural   string
+}
+
+// poEntry is a single translation unit of a PO file.
+type poEntry struct {
+	context     string
+	id          string
+	idPlural    string
+	description []string
+	str         map[int]*string
+	fuzzy       bool
+}
+
+// key returns the message ID of the entry. A msgctxt, when present, prefixes the msgid.
+func (e poEntry) key() string {
+	if e.context != "" {
+		return e.context + "." + e.id
+	}
+
+	return e.id
+}
+
+// unmarshalPO decodes a gettext PO file into the raw key/value form expected by
+// go-i18n. Untranslated and fuzzy entries are skipped, as msgfmt does, and the
+// header entry is only used to read the plural forms.
+func unmarshalPO(data []byte, v interface{}) error {
+	raw, ok := v.(*interface{})
+	if !ok {
+		return fmt.Errorf("po: cannot unmarshal into %T", v)
+	}
+
+	entries, err := parsePO(data)
+	if err != nil {
+		return err
+	}
+
+	header := poHeader{nplurals: 2}
+	var forms map[string]int
+	messages := make(map[string]interface{}, len(entries))
+
+	for _, e := range entries {
+		if e.id == "" {
+			header = parsePOHeader(e.msgstr(0))
+			forms = nil
+			continue
+		}
+
+		if e.fuzzy {
+			continue
+		}
+
+		if e.idPlural == "" {
+			if e.msgstr(0) == "" {
+				continue
+			}
+			if len(e.description) == 0 {
+				messages[e.key()] = e.msgstr(0)
+				continue
+			}
+			messages[e.key()] = map[string]interface{}{
+				"description": strings.Join(e.description, "\n"),
+				"other":       e.msgstr(0),
+			}
+			continue
+		}
+
+		if forms == nil {
+			if forms, err = header.pluralForms(); err != nil {
+				return err
+			}
+		}
+
+		message := make(map[string]interface{})
+		for form, i := range forms {
+			if s := e.msgstr(i); s != "" {
+				message[form] = s
+			}
+		}
+		if len(message) == 0 {
+			continue
+		}
+		if len(e.description) > 0 {
+			message["description"] = strings.Join(e.description, "\n")
+		}
+		messages[e.key()] = message
+	}
+
+	*raw = messages
+
+	return nil
+}
+
+// parsePOHeader reads the Language and Plural-Forms lines of a PO header.
+func parsePOHeader(header string) poHeader {
+	h := poHeader{nplurals: 2}
+	for _, line := range strings.Split(header, "\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "language":
+			if tag, err := language.Parse(strings.TrimSpace(value)); err == nil {
+				h.language = tag
+			}
+		case "plural-forms":
+			for _, part := range strings.Split(value, ";") {
+				k, v, ok := strings.Cut(part, "=")
+				if !ok {
+					continue
+				}
+				switch strings.TrimSpace(k) {
+				case "nplurals":
+					if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+						h.nplurals = n
+					}
+				case "plural":
+					h.plural = strings.TrimSpace(v)
+				}
+			}
+		}
+	}
+
+	return h
+}
+
+// pluralForms maps the CLDR plural forms of the language of the header to the
+// msgstr[n] indexes of plural entries, by evaluating the plural expression and
+// the CLDR rules on the same counts. gettext indexes do not follow a fixed
+// order: index 2 is "many" in Russian but "zero" in Latvian. Forms used only
+// for fractions, such as Russian "other", get the last index. Without a
+// language or a plural expression, only one or two forms can be mapped.
+func (h poHeader) pluralForms() (map[string]int, error) {
+	if h.nplurals < 1 {
+		return nil, fmt.Errorf("po: invalid nplurals=%d", h.nplurals)
+	}
+
+	if h.language == language.Und || h.plural == "" {
+		switch h.nplurals {
+		case 1:
+			return map[string]int{"other": 0}, nil
+		case 2:
+			return map[string]int{"one": 0, "other": 1}, nil
+		}
+		return nil, fmt.Errorf("po: nplurals=%d needs the Language and the plural of the Plural-Forms header", h.nplurals)
+	}
+
+	index, err := parsePluralExpr(h.plural)
+	if err != nil {
+		return nil, err
+	}
+
+	forms := make(map[string]int)
+	for _, n := range poPluralSamples {
+		i := index(n)
+		if i < 0 || i >= h.nplurals {
+			return nil, fmt.Errorf("po: plural expression %q gives %d for %d, out of nplurals=%d", h.plural, i, n, h.nplurals)
+		}
+
+		form := pluralForms[plural.Cardinal.MatchPlural(h.language, n, 0, 0, 0, 0)]
+		if _, ok := forms[form]; !ok {
+			forms[form] = i
+		}
+	}
+
+	if _, ok := forms["other"]; !ok {
+		forms["other"] = h.nplurals - 1
+	}
+
+	return forms, nil
+}
+
+// parsePO splits a PO file into its entries.
+func parsePO(data []byte) ([]poEntry, error) {
+	var (
+		entries []poEntry
+		entry   = poEntry{str: map[int]*string{}}
+		target  *string
+		started bool
+	)
+
+	flush := func() {
+		if started {
+			entries = append(entries, entry)
+		}
+		entry = poEntry{str: map[int]*string{}}
+		target = nil
+		started = false
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "#~"):
+			// Obsolete entries are ignored.
+		case strings.HasPrefix(line, "#."):
+			if started {
+				flush()
+			}
+			entry.description = append(entry.description, strings.TrimSpace(line[2:]))
+		case strings.HasPrefix(line, "#,"):
+			if started {
+				flush()
+			}
+			for _, flag := range strings.Split(line[2:], ",") {
+				if strings.TrimSpace(flag) == "fuzzy" {
+					entry.fuzzy = true
+				}
+			}
+		case strings.HasPrefix(line, "#"):
+			if started {
+				flush()
+			}
+		case strings.HasPrefix(line, `"`):
+			if target == nil {
+				return nil, fmt.Errorf("po: line %d: unexpected string", n)
+			}
+			s, err := strconv.Unquote(line)
+			if err != nil {
+				return nil, fmt.Errorf("po: line %d: %w", n, err)
+			}
+			*target += s
+		default:
+			keyword, value, ok := strings.Cut(line, " ")
+			if !ok {
+				return nil, fmt.Errorf("po: line %d: malformed line", n)
+			}
+			s, err := strconv.Unquote(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("po: line %d: %w", n, err)
+			}
+
+			if (keyword == "msgctxt" || keyword == "msgid") && len(entry.str) > 0 {
+				flush()
+			}
+			started = true
+
+			switch {
+			case keyword == "msgctxt":
+				entry.context = s
+				target = &entry.context
+			case keyword == "msgid":
+				entry.id = s
+				target = &entry.id
+			case keyword == "msgid_plural":
+				entry.idPlural = s
+				target = &entry.idPlural
+			case keyword == "msgstr":
+				target = poTarget(&entry, 0, s)
+			case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+				i, err := strconv.Atoi(keyword[len("msgstr[") : len(keyword)-1])
+				if err != nil {
+					return nil, fmt.Errorf("po: line %d: %w", n, err)
+				}
+				target = poTarget(&entry, i, s)
+			default:
+				return nil, fmt.Errorf("po: line %d: unknown keyword %q", n, keyword)
+			}
+		}
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("po: %w", err)
+	}
+
+	return entries, nil
+}
+
+// poTarget stores s as the n-th msgstr of the entry and returns the string that
+// continuation lines are appended to.
+func poTarget(entry *poEntry, n int, s string) *string {
+	entry.str[n] = &s
+	return entry.str[n]
+}
+
+// msgstr returns the n-th translation of the entry, or an empty string.
+func (e poEntry) msgstr(n int) string {
+	if s, ok := e.str[n]; ok {
+		return *s
+	}
+
+	return ""
+}
@@ -0,0 +1,245 @@
+package translation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pluralExpr is a compiled plural expression of a PO Plural-Forms header,
+// returning the msgstr[n] index of a count.
+type pluralExpr func(n int) int
+
+// pluralOperators are the binary operators of plural expressions, from the
+// lowest precedence to the highest, as in C.
+var pluralOperators = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", ">", "<=", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+// pluralTwoCharOperators are the operators of two characters.
+var pluralTwoCharOperators = map[string]bool{"||": true, "&&": true, "==": true, "!=": true, "<=": true, ">=": true}
+
+// pluralExprParser parses a plural expression such as
+// n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2.
+type pluralExprParser struct {
+	src    string
+	tokens []string
+	pos    int
+}
+
+// parsePluralExpr compiles the plural expression src. A trailing semicolon
+// and enclosing parentheses are allowed.
+func parsePluralExpr(src string) (pluralExpr, error) {
+	p := &pluralExprParser{src: src}
+	if err := p.tokenize(strings.TrimSuffix(strings.TrimSpace(src), ";")); err != nil {
+		return nil, err
+	}
+
+	expr, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, p.errorf("unexpected %q", p.tokens[p.pos])
+	}
+
+	return expr, nil
+}
+
+func (p *pluralExprParser) tokenize(src string) error {
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(src) && src[j] >= '0' && src[j] <= '9' {
+				j++
+			}
+			p.tokens = append(p.tokens, src[i:j])
+			i = j
+		case i+1 < len(src) && pluralTwoCharOperators[src[i:i+2]]:
+			p.tokens = append(p.tokens, src[i:i+2])
+			i += 2
+		case strings.IndexByte("n?:<>+-*/%!()", c) >= 0:
+			p.tokens = append(p.tokens, src[i:i+1])
+			i++
+		default:
+			return p.errorf("unexpected %q", c)
+		}
+	}
+
+	return nil
+}
+
+// ternary parses condition ? then : else, or a binary expression.
+func (p *pluralExprParser) ternary() (pluralExpr, error) {
+	cond, err := p.binary(0)
+	if err != nil || !p.accept("?") {
+		return cond, err
+	}
+
+	then, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept(":") {
+		return nil, p.errorf("expected :")
+	}
+	otherwise, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(n int) int {
+		if cond(n) != 0 {
+			return then(n)
+		}
+		return otherwise(n)
+	}, nil
+}
+
+// binary parses the operators of pluralOperators from level up.
+func (p *pluralExprParser) binary(level int) (pluralExpr, error) {
+	if level == len(pluralOperators) {
+		return p.unary()
+	}
+
+	left, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op := ""
+		for _, candidate := range pluralOperators[level] {
+			if p.accept(candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			return left, nil
+		}
+
+		right, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = pluralOperation(op, left, right)
+	}
+}
+
+func (p *pluralExprParser) unary() (pluralExpr, error) {
+	if !p.accept("!") {
+		return p.primary()
+	}
+
+	operand, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(n int) int { return pluralBool(operand(n) == 0) }, nil
+}
+
+func (p *pluralExprParser) primary() (pluralExpr, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, p.errorf("unexpected end")
+	}
+
+	token := p.tokens[p.pos]
+	p.pos++
+
+	switch {
+	case token == "n":
+		return func(n int) int { return n }, nil
+	case token == "(":
+		expr, err := p.ternary()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, p.errorf("expected )")
+		}
+		return expr, nil
+	case token[0] >= '0' && token[0] <= '9':
+		value, err := strconv.Atoi(token)
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		return func(int) int { return value }, nil
+	}
+
+	return nil, p.errorf("unexpected %q", token)
+}
+
+// accept consumes the next token when it is token.
+func (p *pluralExprParser) accept(token string) bool {
+	if p.pos < len(p.tokens) && p.tokens[p.pos] == token {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *pluralExprParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("po: invalid plural expression %q: %s", p.src, fmt.Sprintf(format, args...))
+}
+
+// pluralOperation returns the expression applying the binary operator op.
+// Division by zero gives 0 rather than panicking.
+func pluralOperation(op string, left, right pluralExpr) pluralExpr {
+	return func(n int) int {
+		a, b := left(n), right(n)
+		switch op {
+		case "||":
+			return pluralBool(a != 0 || b != 0)
+		case "&&":
+			return pluralBool(a != 0 && b != 0)
+		case "==":
+			return pluralBool(a == b)
+		case "!=":
+			return pluralBool(a != b)
+		case "<":
+			return pluralBool(a < b)
+		case ">":
+			return pluralBool(a > b)
+		case "<=":
+			return pluralBool(a <= b)
+		case ">=":
+			return pluralBool(a >= b)
+		case "+":
+			return a + b
+		case "-":
+			return a - b
+		case "*":
+			return a * b
+		case "/":
+			if b == 0 {
+				return 0
+			}
+			return a / b
+		default:
+			if b == 0 {
+				return 0
+			}
+			return a % b
+		}
+	}
+}
+
+func pluralBool(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,150 @@
+package translation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+const poStub = `# Persian translations.
+msgid ""
+msgstr ""
+"Language: fa\n"
+"Plural-Forms: nplurals=2; plural=(n > 1);\n"
+
+#. Shown when a user signs in.
+msgid "greeting"
+msgstr "سلام "
+"{{.name}}"
+
+msgctxt "server.errors"
+msgid "something_is_wrong"
+msgstr "مشکلی پیش آمده است"
+
+msgid "items"
+msgid_plural "items"
+msgstr[0] "{{.count}} item"
+msgstr[1] "{{.count}} items"
+
+msgid "untranslated"
+msgstr ""
+
+#~ msgid "obsolete"
+#~ msgstr "obsolete"
+`
+
+func TestUnmarshalPO(t *testing.T) {
+	var raw interface{}
+	assert.NoError(t, unmarshalPO([]byte(poStub), &raw))
+
+	messages := raw.(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"description": "Shown when a user signs in.",
+		"other":       "سلام {{.name}}",
+	}, messages["greeting"])
+	assert.Equal(t, "مشکلی پیش آمده است", messages["server.errors.something_is_wrong"])
+	assert.Equal(t, map[string]interface{}{
+		"one":   "{{.count}} item",
+		"other": "{{.count}} items",
+	}, messages["items"])
+	assert.NotContains(t, messages, "untranslated")
+	assert.NotContains(t, messages, "obsolete")
+}
+
+func TestUnmarshalPO_Malformed(t *testing.T) {
+	var raw interface{}
+	assert.Error(t, unmarshalPO([]byte(`msgid "greeting`), &raw))
+	assert.Error(t, unmarshalPO([]byte(`"orphan"`), &raw))
+}
+
+func TestParsePOHeader(t *testing.T) {
+	h := parsePOHeader("Language: ru\nPlural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : 1);\n")
+	assert.Equal(t, language.Russian, h.language)
+	assert.Equal(t, 3, h.nplurals)
+	assert.Equal(t, "(n%10==1 && n%100!=11 ? 0 : 1)", h.plural)
+
+	h = parsePOHeader("Project-Id-Version: app\n")
+	assert.Equal(t, language.Und, h.language)
+	assert.Equal(t, 2, h.nplurals)
+}
+
+func TestParsePluralExpr(t *testing.T) {
+	expr, err := parsePluralExpr("(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);")
+	assert.NoError(t, err)
+	for n, want := range map[int]int{1: 0, 11: 2, 21: 0, 3: 1, 13: 2, 24: 1, 5: 2, 0: 2} {
+		assert.Equal(t, want, expr(n), "n=%d", n)
+	}
+
+	expr, err = parsePluralExpr("!(n != 1) + n / 0")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, expr(1))
+
+	for _, src := range []string{"n ? 1", "(n > 1", "n = 1", "n >"} {
+		_, err := parsePluralExpr(src)
+		assert.Error(t, err, src)
+	}
+}
+
+func TestUnmarshalPO_PluralForms(t *testing.T) {
+	ru := `msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgid "files"
+msgid_plural "files"
+msgstr[0] "{{.count}} файл"
+msgstr[1] "{{.count}} файла"
+msgstr[2] "{{.count}} файлов"
+
+#, fuzzy
+msgid "folders"
+msgstr "папки"
+`
+	var raw interface{}
+	assert.NoError(t, unmarshalPO([]byte(ru), &raw))
+	messages := raw.(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"one":   "{{.count}} файл",
+		"few":   "{{.count}} файла",
+		"many":  "{{.count}} файлов",
+		"other": "{{.count}} файлов",
+	}, messages["files"])
+	assert.NotContains(t, messages, "folders")
+
+	trans := newTranslationStub(t, Config{Locale: "ru"}, map[string]string{"ru.po": ru})
+	assert.Equal(t, "5 файлов", trans.TransPlural("files", 5, nil))
+	assert.Equal(t, "22 файла", trans.TransPlural("files", 22, nil))
+	assert.Equal(t, "folders", trans.Trans("folders", nil))
+
+	lv := `msgid ""
+msgstr ""
+"Language: lv\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n != 0 ? 1 : 2);\n"
+
+msgid "days"
+msgid_plural "days"
+msgstr[0] "diena"
+msgstr[1] "dienas"
+msgstr[2] "dienu"
+`
+	assert.NoError(t, unmarshalPO([]byte(lv), &raw))
+	assert.Equal(t, map[string]interface{}{
+		"zero":  "dienu",
+		"one":   "diena",
+		"other": "dienas",
+	}, raw.(map[string]interface{})["days"])
+
+	noLanguage := `msgid ""
+msgstr ""
+"Plural-Forms: nplurals=3; plural=(n==1 ? 0 : n<5 ? 1 : 2);\n"
+
+msgid "files"
+msgid_plural "files"
+msgstr[0] "file"
+msgstr[1] "files"
+msgstr[2] "files"
+`
+	assert.ErrorContains(t, unmarshalPO([]byte(noLanguage), &raw), "nplurals=3")
+}
@@ -0,0 +1,44 @@
+package translation
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+// pseudoLetters are the accented replacements of the ASCII letters.
+var pseudoLetters = map[rune]rune{
+	'a': 'å', 'b': 'ƀ', 'c': 'ç', 'd': 'ð', 'e': 'é', 'f': 'ƒ', 'g': 'ĝ', 'h': 'ĥ', 'i': 'î',
+	'j': 'ĵ', 'k': 'ķ', 'l': 'ļ', 'm': 'ɱ', 'n': 'ñ', 'o': 'ö', 'p': 'þ', 'q': 'ǫ', 'r': 'ŕ',
+	's': 'š', 't': 'ţ', 'u': 'û', 'v': 'ṽ', 'w': 'ŵ', 'x': 'ẋ', 'y': 'ý', 'z': 'ž',
+	'A': 'Å', 'B': 'Ɓ', 'C': 'Ç', 'D': 'Ð', 'E': 'É', 'F': 'Ƒ', 'G': 'Ĝ', 'H': 'Ĥ', 'I': 'Î',
+	'J': 'Ĵ', 'K': 'Ķ', 'L': 'Ļ', 'M': 'Ṁ', 'N': 'Ñ', 'O': 'Ö', 'P': 'Þ', 'Q': 'Ǫ', 'R': 'Ŕ',
+	'S': 'Š', 'T': 'Ţ', 'U': 'Û', 'V': 'Ṽ', 'W': 'Ŵ', 'X': 'Ẋ', 'Y': 'Ý', 'Z': 'Ž',
+}
+
+// isPseudo reports whether tag is Config.PseudoLocale.
+func (t *translation) isPseudo(tag language.Tag) bool {
+	return t.pseudo != language.Und && tag == t.pseudo
+}
+
+// pseudolocalize accents the letters of message, pads it by about 30% and
+// wraps it in brackets, e.g. Save becomes [Šåṽé ·].
+func pseudolocalize(message string) string {
+	var b strings.Builder
+	b.WriteString("[")
+	for _, r := range message {
+		if accented, ok := pseudoLetters[r]; ok {
+			r = accented
+		}
+		b.WriteRune(r)
+	}
+
+	if padding := (utf8.RuneCountInString(message)*3 + 9) / 10; padding > 0 {
+		b.WriteString(" ")
+		b.WriteString(strings.Repeat("·", padding))
+	}
+	b.WriteString("]")
+
+	return b.String()
+}
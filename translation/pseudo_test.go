@@ -0,0 +1,58 @@
+package translation
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslation_PseudoLocale(t *testing.T) {
+	trans := newTranslationStub(t, Config{
+		Locale:       "fa",
+		PseudoLocale: "en-XA",
+	}, map[string]string{
+		"fa.json": `{"greeting": "سلام {{.name}}", "save": "ذخیره"}`,
+		"en.json": `{"greeting": "Hello {{.name}}", "items": {"one": "{{.count}} item", "other": "{{.count}} items"}}`,
+	})
+
+	assert.Equal(t, "[Ĥéļļö Šåŕå ···]", trans.Trans("greeting", map[string]interface{}{"name": "Sara"}, "en-XA"))
+	assert.Equal(t, "[2 îţéɱš ···]", trans.TransPlural("items", 2, nil, "en-XA"))
+	assert.Equal(t, "save", trans.Trans("save", nil, "en-XA"))
+	assert.Equal(t, "missing", trans.Trans("missing", nil, "en-XA"))
+	assert.Equal(t, "Hello Sara", trans.Trans("greeting", map[string]interface{}{"name": "Sara"}, "en"))
+	assert.Equal(t, "سلام Sara", trans.Trans("greeting", map[string]interface{}{"name": "Sara"}))
+}
+
+func TestTranslation_NegotiatePseudoLocale(t *testing.T) {
+	trans := newTranslationStub(t, Config{
+		Locale:       "fa",
+		PseudoLocale: "en-XA",
+	}, map[string]string{"en.json": `{"greeting": "Hello"}`})
+
+	assert.Equal(t, "en-XA", trans.Negotiate("en-XA").String())
+	assert.Equal(t, "en", trans.Negotiate("en-US,en-XA;q=0.5").String())
+
+	disabled := newTranslationStub(t, Config{Locale: "fa"}, map[string]string{"en.json": `{"greeting": "Hello"}`})
+	assert.Equal(t, "en", disabled.Negotiate("en-XA").String())
+	assert.Equal(t, "Hello", disabled.Trans("greeting", nil, "en-XA"))
+}
+
+func TestTranslation_UnknownPseudoLocale(t *testing.T) {
+	var buf bytes.Buffer
+	trans := newTranslationStub(t, Config{
+		Locale:       "en",
+		PseudoLocale: "xx-PS",
+		Logger:       slog.New(slog.NewTextHandler(&buf, nil)),
+	}, map[string]string{"en.json": `{"greeting": "Hello"}`})
+
+	assert.Contains(t, buf.String(), `level=ERROR msg="translation: invalid pseudo locale" language=xx-PS`)
+	assert.Equal(t, "Hello", trans.Trans("greeting", nil, "xx-PS"))
+}
+
+func TestPseudolocalize(t *testing.T) {
+	assert.Equal(t, "[Šåṽé ··]", pseudolocalize("Save"))
+	assert.Equal(t, "[]", pseudolocalize(""))
+	assert.Equal(t, "[Ţĥé ǫûîçķ ƀŕöŵñ ƒöẋ ······]", pseudolocalize("The quick brown fox"))
+}
@@ -0,0 +1,145 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Store provides messages kept outside of the message files, see Config.Store.
+type Store interface {
+	// Messages returns the messages of the store by language.
+	Messages(ctx context.Context) (map[language.Tag][]*i18n.Message, error)
+}
+
+// storeError is an error returned by Config.Store while loading messages.
+type storeError struct {
+	err error
+}
+
+func (e storeError) Error() string {
+	return e.err.Error()
+}
+
+func (e storeError) Unwrap() error {
+	return e.err
+}
+
+const defaultStoreTable = "translation_messages"
+
+// StoredMessage is a row of the table of a GormStore. A simple message only
+// has Other; a plural message has a column per plural form.
+type StoredMessage struct {
+	ID        uint   `gorm:"primaryKey"`
+	Language  string `gorm:"size:35;not null;uniqueIndex:idx_translation_messages_language_key"`
+	Key       string `gorm:"size:255;not null;uniqueIndex:idx_translation_messages_language_key"`
+	Zero      string `gorm:"type:text"`
+	One       string `gorm:"type:text"`
+	Two       string `gorm:"type:text"`
+	Few       string `gorm:"type:text"`
+	Many      string `gorm:"type:text"`
+	Other     string `gorm:"type:text"`
+	UpdatedAt time.Time
+}
+
+// GormStore is a Store reading messages from a database table with gorm.
+type GormStore struct {
+	db    *gorm.DB
+	table string
+}
+
+// NewGormStore creates a store on the table, translation_messages by default.
+func NewGormStore(db *gorm.DB, table ...string) *GormStore {
+	store := &GormStore{db: db, table: defaultStoreTable}
+	if len(table) > 0 && table[0] != "" {
+		store.table = table[0]
+	}
+
+	return store
+}
+
+// Migrate creates or updates the table of the store.
+func (s *GormStore) Migrate() error {
+	return s.db.Table(s.table).AutoMigrate(&StoredMessage{})
+}
+
+// Messages returns the messages of the table by language. A row with an
+// invalid language is skipped and reported in the returned error.
+func (s *GormStore) Messages(ctx context.Context) (map[language.Tag][]*i18n.Message, error) {
+	var rows []StoredMessage
+	if err := s.db.WithContext(ctx).Table(s.table).Order("id").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+
+	var errs []error
+	messages := make(map[language.Tag][]*i18n.Message)
+	for _, row := range rows {
+		tag, err := language.Parse(row.Language)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("store: message %q: %w", row.Key, err))
+			continue
+		}
+
+		messages[tag] = append(messages[tag], &i18n.Message{
+			ID:    row.Key,
+			Zero:  row.Zero,
+			One:   row.One,
+			Two:   row.Two,
+			Few:   row.Few,
+			Many:  row.Many,
+			Other: row.Other,
+		})
+	}
+
+	return messages, errors.Join(errs...)
+}
+
+// Save inserts or updates the message of lang. Call Translation.Reload to
+// serve the change.
+func (s *GormStore) Save(ctx context.Context, lang string, message *i18n.Message) error {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return err
+	}
+
+	row := StoredMessage{
+		Language: tag.String(),
+		Key:      message.ID,
+		Zero:     message.Zero,
+		One:      message.One,
+		Two:      message.Two,
+		Few:      message.Few,
+		Many:     message.Many,
+		Other:    message.Other,
+	}
+
+	return s.db.WithContext(ctx).Table(s.table).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "language"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"zero", "one", "two", "few", "many", "other", "updated_at"}),
+	}).Create(&row).Error
+}
+
+// Delete removes the message of lang with the key, so the message of the
+// files is served again after a reload.
+func (s *GormStore) Delete(ctx context.Context, lang, key string) error {
+	if key == "" {
+		return errors.New("store: empty message key")
+	}
+
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return err
+	}
+
+	// A map keeps zero values in the condition, unlike a struct, and gorm
+	// quotes the key column, a reserved word in MySQL.
+	return s.db.WithContext(ctx).Table(s.table).
+		Where(map[string]interface{}{"language": tag.String(), "key": key}).
+		Delete(&StoredMessage{}).Error
+}
@@ -0,0 +1,71 @@
+package translation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestGormStore(t *testing.T) {
+	ctx := context.Background()
+	open := func(t *testing.T) *GormStore {
+		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		store := NewGormStore(db)
+		if err := store.Migrate(); err != nil {
+			t.Fatal(err)
+		}
+
+		return store
+	}
+
+	t.Run("overrides files", func(t *testing.T) {
+		store := open(t)
+		assert.NoError(t, store.Save(ctx, "fa", &i18n.Message{ID: "greeting", Other: "درود"}))
+		assert.NoError(t, store.Save(ctx, "en", &i18n.Message{ID: "items", One: "{{.count}} item", Other: "{{.count}} items"}))
+
+		trans := newTranslationStub(t, Config{Locale: "fa", Store: store}, map[string]string{
+			"fa.json": `{"greeting": "سلام", "farewell": "خداحافظ"}`,
+			"en.json": `{"greeting": "Hello"}`,
+		})
+
+		assert.Equal(t, "درود", trans.Trans("greeting", nil))
+		assert.Equal(t, "خداحافظ", trans.Trans("farewell", nil))
+		assert.Equal(t, "Hello", trans.Trans("greeting", nil, "en"))
+		assert.Equal(t, "1 item", trans.TransPlural("items", 1, nil, "en"))
+
+		assert.NoError(t, store.Save(ctx, "fa", &i18n.Message{ID: "greeting", Other: "سلام علیکم"}))
+		assert.Equal(t, "درود", trans.Trans("greeting", nil))
+		assert.NoError(t, trans.Reload())
+		assert.Equal(t, "سلام علیکم", trans.Trans("greeting", nil))
+
+		assert.NoError(t, store.Save(ctx, "fa", &i18n.Message{ID: "farewell", Other: "بدرود"}))
+		assert.Error(t, store.Delete(ctx, "fa", ""))
+		assert.NoError(t, store.Delete(ctx, "fa", "greeting"))
+		assert.NoError(t, trans.Reload())
+		assert.Equal(t, "سلام", trans.Trans("greeting", nil))
+		assert.Equal(t, "بدرود", trans.Trans("farewell", nil))
+	})
+
+	t.Run("invalid language", func(t *testing.T) {
+		store := open(t)
+		assert.NoError(t, store.Save(ctx, "fa", &i18n.Message{ID: "greeting", Other: "سلام"}))
+		assert.NoError(t, store.db.Table(store.table).Create(&StoredMessage{Language: "not a language", Key: "greeting", Other: "?"}).Error)
+
+		messages, err := store.Messages(ctx)
+		assert.Error(t, err)
+		assert.Len(t, messages, 1)
+
+		trans := newTranslationStub(t, Config{Locale: "fa", Store: store, Logger: NopLogger}, nil)
+		assert.Equal(t, "سلام", trans.Trans("greeting", nil))
+		assert.Error(t, trans.Reload())
+	})
+}
@@ -1,11 +1,17 @@
 package translation
 
 import (
-	"encoding/json"
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"golang.org/x/text/language"
@@ -13,124 +19,519 @@ import (
 
 type Translation interface {
 	Trans(key string, args map[string]interface{}, languages ...string) string
+	TransCtx(ctx context.Context, key string, args map[string]interface{}) string
+	TransPlural(key string, count interface{}, args map[string]interface{}, languages ...string) string
+	Namespace(ns string) Translation
 	GetLocalization(lang string) *i18n.Localizer
+	Negotiate(preferences ...string) language.Tag
+	FormatNumber(value interface{}, languages ...string) string
+	FormatPercent(value interface{}, languages ...string) string
+	FormatCurrency(amount interface{}, code string, languages ...string) string
+	FormatDate(value time.Time, languages ...string) string
+	FormatRelativeTime(value time.Time, languages ...string) string
+	Languages() []language.Tag
+	Messages(lang string) []*i18n.Message
+	Catalog(languages ...string) map[string]*i18n.Message
+	Locale(languages ...string) Locale
+	Locales() []Locale
+	Reload() error
+	Watch(ctx context.Context, interval time.Duration)
 }
 
+// CountKey is the template argument holding the count of a plural message.
+const CountKey = "count"
+
 var (
-	trans *translation
-	once  sync.Once
+	defaultTrans Translation
+	defaultMu    sync.RWMutex
 )
 
-// Trans is a helper function that translates a message.
+// SetDefault registers t as the instance used by the package-level Trans helper.
+// Passing nil unregisters the current default.
+func SetDefault(t Translation) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	defaultTrans = t
+}
+
+// Default returns the instance registered with SetDefault, or nil if there is none.
+func Default() Translation {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+
+	return defaultTrans
+}
+
+// Trans is a helper function that translates a message with the default instance.
+// If no default has been registered the key itself is returned.
 func Trans(key string, args map[string]interface{}, languages ...string) string {
-	return trans.Trans(key, args, languages...)
+	t := Default()
+	if t == nil {
+		return key
+	}
+
+	return t.Trans(key, args, languages...)
 }
 
 type translation struct {
-	config         Config
-	acceptLanguage *i18n.Localizer
-	bundle         *i18n.Bundle
+	config  Config
+	pseudo  language.Tag
+	mu      sync.RWMutex
+	current *snapshot
+}
+
+// maxCachedChains bounds the number of requested languages whose fallback chain
+// is cached by a snapshot, since languages may come straight from clients.
+const maxCachedChains = 256
+
+// snapshot is everything loaded from the locale sources, swapped as a whole on reload.
+// Localizers and fallback chains are cached on it, so a reload drops them too.
+type snapshot struct {
+	bundle   *i18n.Bundle
+	matcher  language.Matcher
+	messages map[language.Tag]map[string]*i18n.Message
+	version  string
+	// storeFailed is set when Config.Store failed to load its messages.
+	storeFailed bool
+	localizers  sync.Map // language.Tag -> *i18n.Localizer
+	parsers     sync.Map // language.Tag -> *templateParser
+	chains      sync.Map // string -> []language.Tag
+	chainCount  atomic.Int32
 }
 
 // NewTranslation creates a new translation instance.
+// Every call returns an independent instance with its own bundle, so several
+// configurations can live side by side in one process. Use SetDefault to make
+// an instance available to the package-level Trans helper.
 func NewTranslation(c Config) Translation {
-	once.Do(func() {
-		trans = &translation{
-			config: c,
+	trans := &translation{
+		config: c,
+	}
+
+	if c.PseudoLocale != "" {
+		if tag, err := language.Parse(c.PseudoLocale); err == nil {
+			trans.pseudo = tag
+		} else {
+			trans.logger().Error("translation: invalid pseudo locale", "language", c.PseudoLocale, "error", err)
 		}
+	}
+
+	version, _ := trans.fingerprint()
+	current, err := trans.newSnapshot(version)
+	if err != nil {
+		trans.logger().Error("translation: failed to load message files", "error", err)
+	}
+	trans.swap(current)
+
+	return trans
+}
+
+// Reload rebuilds the bundle from the locale path and swaps it in atomically.
+// On failure the previously loaded bundle is kept.
+func (t *translation) Reload() error {
+	version, _ := t.fingerprint()
+	current, err := t.newSnapshot(version)
+	if err != nil {
+		return err
+	}
+
+	t.swap(current)
 
-		trans.createLocalePathIfNotExists(c.PathLocale)
-		trans.bundle = i18n.NewBundle(language.English)
-		trans.bundle.RegisterUnmarshalFunc("json", json.Unmarshal)
+	return nil
+}
+
+// swap replaces the snapshot in use.
+func (t *translation) swap(current *snapshot) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.current = current
+}
+
+// snapshot returns the snapshot in use.
+func (t *translation) snapshot() *snapshot {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
 
-		if err := trans.walkingInLocalePath(c.PathLocale); err != nil {
-			log.Printf("Failed to walk in locale path: %s", err.Error())
+	return t.current
+}
+
+// newSnapshot creates a bundle and loads every message file found in the locale
+// sources, then the messages of Config.Store.
+func (t *translation) newSnapshot(version string) (*snapshot, error) {
+	var (
+		files       []*i18n.MessageFile
+		errs        []error
+		storeFailed bool
+	)
+
+	for _, source := range t.localeSources() {
+		loaded, err := t.walkingInLocaleFS(source)
+		files = append(files, loaded...)
+		errs = append(errs, err)
+	}
+
+	if t.config.Store != nil {
+		loaded, err := t.config.Store.Messages(context.Background())
+		for tag, messages := range loaded {
+			files = append(files, &i18n.MessageFile{Path: "store", Tag: tag, Messages: messages})
+		}
+		if err != nil {
+			storeFailed = true
+			errs = append(errs, storeError{err: err})
 		}
+	}
+
+	// Languages without a region or script are added first: the matcher picks
+	// the first of equivalent tags, so fa must be registered before fa-IR for a
+	// request in fa to be served by fa.json. The sort is stable so that later
+	// sources still override earlier ones.
+	sort.SliceStable(files, func(i, j int) bool {
+		return specificity(files[i].Tag) < specificity(files[j].Tag)
 	})
 
-	return trans
+	bundle := i18n.NewBundle(t.bundleLanguage())
+	messages := make(map[language.Tag]map[string]*i18n.Message)
+	for _, file := range files {
+		if err := bundle.AddMessages(file.Tag, file.Messages...); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file.Path, err))
+			continue
+		}
+
+		if messages[file.Tag] == nil {
+			messages[file.Tag] = make(map[string]*i18n.Message)
+		}
+		for _, message := range file.Messages {
+			// An empty message, such as a skeleton entry, is not a translation.
+			if i18n.NewMessageTemplate(message) == nil {
+				delete(messages[file.Tag], message.ID)
+				continue
+			}
+			messages[file.Tag][message.ID] = message
+		}
+	}
+
+	return &snapshot{
+		bundle:      bundle,
+		matcher:     language.NewMatcher(bundle.LanguageTags()),
+		messages:    messages,
+		version:     version,
+		storeFailed: storeFailed,
+	}, errors.Join(errs...)
 }
 
-// GetLocalization initializes the localizer with the desired language.
-func (t translation) GetLocalization(lang string) *i18n.Localizer {
+// specificity returns the number of subtags of tag.
+func specificity(tag language.Tag) int {
+	return strings.Count(tag.String(), "-")
+}
+
+// Negotiate returns the loaded language that best matches the preferences.
+// Each preference is a language tag or an Accept-Language value with q-weights,
+// given in decreasing priority. When nothing matches, Config.Locale is returned.
+// Config.PseudoLocale is returned when it is the preferred language.
+func (t *translation) Negotiate(preferences ...string) language.Tag {
+	var tags []language.Tag
+	for _, preference := range preferences {
+		if preference == "" {
+			continue
+		}
+
+		parsed, _, err := language.ParseAcceptLanguage(preference)
+		if err != nil {
+			continue
+		}
+		tags = append(tags, parsed...)
+	}
+
+	if len(tags) > 0 && t.isPseudo(tags[0]) {
+		return tags[0]
+	}
+
+	if len(tags) > 0 {
+		current := t.snapshot()
+		if _, i, confidence := current.matcher.Match(tags...); confidence != language.No {
+			return current.bundle.LanguageTags()[i]
+		}
+	}
+
+	if tag, err := language.Parse(t.config.Locale); err == nil {
+		return tag
+	}
+
+	return t.bundleLanguage()
+}
+
+// GetLocalization returns the cached localizer of the loaded language closest to lang.
+func (t *translation) GetLocalization(lang string) *i18n.Localizer {
 	if lang == "" {
 		lang = t.config.Locale
 	}
 
 	tag, err := language.Parse(lang)
 	if err != nil {
-		tag = language.English
-		log.Printf("Failed to parse language tag: %s", err.Error())
+		tag = t.bundleLanguage()
+		t.logger().Warn("translation: invalid language tag", "language", lang, "error", err)
 	}
 
-	t.acceptLanguage = i18n.NewLocalizer(t.bundle, tag.String())
+	current := t.snapshot()
+	if loaded, ok := current.loadedLanguage(tag); ok {
+		return current.localizer(loaded)
+	}
 
-	return t.acceptLanguage
+	return current.localizer(t.bundleLanguage())
 }
 
 // Trans is a helper function that translates a message.
-func (t translation) Trans(key string, args map[string]interface{}, languages ...string) string {
-	config := &i18n.LocalizeConfig{
+func (t *translation) Trans(key string, args map[string]interface{}, languages ...string) string {
+	return t.localize(&i18n.LocalizeConfig{
 		MessageID:    key,
 		TemplateData: args,
+	}, languages...)
+}
+
+// TransPlural translates a message choosing its CLDR plural form (zero, one,
+// two, few, many or other) for count in the resolved language. Unless args
+// already holds it, count is also passed to the template under CountKey.
+func (t *translation) TransPlural(key string, count interface{}, args map[string]interface{}, languages ...string) string {
+	data := make(map[string]interface{}, len(args)+1)
+	for k, v := range args {
+		data[k] = v
+	}
+
+	if _, ok := data[CountKey]; !ok {
+		data[CountKey] = count
 	}
 
-	if len(languages) > 0 {
-		t.acceptLanguage = t.GetLocalization(languages[0])
+	return t.localize(&i18n.LocalizeConfig{
+		MessageID:    key,
+		TemplateData: data,
+		PluralCount:  count,
+	}, languages...)
+}
+
+// localize resolves the message described by config, returning its ID on failure.
+func (t *translation) localize(config *i18n.LocalizeConfig, languages ...string) string {
+	lang := t.requestedLanguage(languages...)
+
+	message, err := t.lookup(config, lang)
+	if err == nil {
+		if t.pseudo != language.Und {
+			if tag, err := language.Parse(lang); err == nil && t.isPseudo(tag) {
+				return pseudolocalize(message)
+			}
+		}
+		return message
+	}
+
+	var notFound *i18n.MessageNotFoundErr
+	if errors.As(err, &notFound) {
+		t.missing(lang, config.MessageID)
 	} else {
-		t.acceptLanguage = t.GetLocalization(t.config.Locale)
+		t.logger().Error("translation: failed to localize message", "key", config.MessageID, "error", err)
 	}
 
-	message, err := t.acceptLanguage.Localize(config)
-	if err != nil {
-		defaultLang := i18n.NewLocalizer(t.bundle)
-		if message, err = defaultLang.Localize(config); err != nil {
-			log.Printf("Failed to localize message: %s", err.Error())
-			return key
-		} else {
-			return message
+	return config.MessageID
+}
+
+// lookup resolves the message described by config without reporting failures.
+// The languages of the fallback chain are tried in order until one has the
+// message, whose template is executed with the functions of that language.
+func (t *translation) lookup(config *i18n.LocalizeConfig, lang string) (string, error) {
+	current := t.snapshot()
+
+	var err error = &i18n.MessageNotFoundErr{Tag: language.Make(lang), MessageID: config.MessageID}
+	for _, tag := range t.loadedChain(current, lang) {
+		c := *config
+		c.TemplateParser = current.parser(t, tag)
+
+		var message string
+		if message, err = current.localizer(tag).Localize(&c); err == nil {
+			return message, nil
 		}
 	}
 
-	return message
+	return "", err
+}
+
+// requestedLanguage returns the first of languages, or Config.Locale.
+func (t *translation) requestedLanguage(languages ...string) string {
+	if len(languages) > 0 && languages[0] != "" {
+		return languages[0]
+	}
+
+	return t.config.Locale
 }
 
-// createLocaleDirectory creates a directory for the locale files.
-func (t translation) createLocalePathIfNotExists(path string) {
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		log.Printf("Folder fa did not exist! We made it by default :)")
-		if err = os.MkdirAll(path, os.ModePerm); err != nil {
-			log.Printf("Failed to create locale path: %s", err.Error())
-			return
+// loadedChain returns the loaded languages serving the fallback chain of lang.
+func (t *translation) loadedChain(current *snapshot, lang string) []language.Tag {
+	if chain, ok := current.chains.Load(lang); ok {
+		return chain.([]language.Tag)
+	}
+
+	var chain []language.Tag
+	for _, tag := range t.fallbackChain(lang) {
+		if loaded, ok := current.loadedLanguage(tag); ok {
+			chain = append(chain, loaded)
 		}
 	}
 
-	if _, err := os.Stat(path + "/fa.json"); !os.IsNotExist(err) {
-		return
+	if current.chainCount.Add(1) <= maxCachedChains {
+		current.chains.Store(lang, chain)
+	}
+
+	return chain
+}
+
+// localizer returns the cached localizer of tag. Localizers are safe for concurrent use.
+func (s *snapshot) localizer(tag language.Tag) *i18n.Localizer {
+	if localizer, ok := s.localizers.Load(tag); ok {
+		return localizer.(*i18n.Localizer)
+	}
+
+	localizer, _ := s.localizers.LoadOrStore(tag, i18n.NewLocalizer(s.bundle, tag.String()))
+
+	return localizer.(*i18n.Localizer)
+}
+
+// loadedLanguage returns the language of the bundle that serves tag: tag itself
+// when it is loaded, otherwise the closest language with at least high confidence.
+func (s *snapshot) loadedLanguage(tag language.Tag) (language.Tag, bool) {
+	if _, ok := s.messages[tag]; ok {
+		return tag, true
+	}
+
+	_, i, confidence := s.matcher.Match(tag)
+	if confidence < language.High {
+		return language.Und, false
+	}
+
+	return s.bundle.LanguageTags()[i], true
+}
+
+// fallbackChain returns the languages a message is looked up in, in order: the
+// requested language, its parents without region and script, Config.FallbackLocale
+// and finally the bundle default language.
+func (t *translation) fallbackChain(lang string) []language.Tag {
+	var chain []language.Tag
+	add := func(tag language.Tag) {
+		for _, c := range chain {
+			if c == tag {
+				return
+			}
+		}
+		chain = append(chain, tag)
 	}
 
-	if _, err := os.Create(path + "/fa.json"); err != nil {
-		log.Printf("Failed to create the default locale file: %s", err.Error())
-		return
+	if tag, err := language.Parse(lang); lang == "" {
+		// Nothing was requested, the fallbacks below apply.
+	} else if err == nil {
+		add(tag)
+
+		base, _ := tag.Base()
+		if script, confidence := tag.Script(); confidence == language.Exact {
+			if parent, err := language.Compose(base, script); err == nil {
+				add(parent)
+			}
+		}
+		if parent, err := language.Compose(base); err == nil {
+			add(parent)
+		}
 	} else {
-		log.Printf("File fa.json did not exist! We made it by default :)")
+		t.logger().Warn("translation: invalid language tag", "language", lang, "error", err)
+	}
+
+	if tag, err := language.Parse(t.config.FallbackLocale); err == nil {
+		add(tag)
+	}
+
+	add(t.bundleLanguage())
+
+	return chain
+}
+
+// bundleLanguage returns the default language of the bundle, Config.BundleLocale or English.
+func (t *translation) bundleLanguage() language.Tag {
+	if tag, err := language.Parse(t.config.BundleLocale); err == nil {
+		return tag
 	}
+
+	return language.English
 }
 
-// walkingInLocalePath walks in the locale path and loads the message files.
-func (t translation) walkingInLocalePath(path string) error {
-	return filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+// localeSource is a file system that message files are loaded from.
+type localeSource struct {
+	name string
+	fsys fs.FS
+}
+
+// localeSources returns the sources to load message files from, in increasing
+// priority: Config.FS first, then Config.PathLocale overriding it on disk.
+func (t *translation) localeSources() []localeSource {
+	var sources []localeSource
+
+	if t.config.FS != nil {
+		sources = append(sources, localeSource{name: "fs", fsys: t.config.FS})
+	}
+
+	if t.config.PathLocale != "" {
+		sources = append(sources, localeSource{name: t.config.PathLocale, fsys: os.DirFS(t.config.PathLocale)})
+	}
+
+	return sources
+}
+
+// walkingInLocaleFS walks in the locale source and parses the message files.
+// A missing source is treated as empty. A file that fails to parse, or that
+// defines a message already defined by another file of the source when
+// namespaces are enabled, does not stop the walk; all such errors are returned
+// joined. Without namespaces, the last file defining a message wins.
+func (t *translation) walkingInLocaleFS(source localeSource) ([]*i18n.MessageFile, error) {
+	var (
+		files       []*i18n.MessageFile
+		errs        []error
+		definitions = make(messageDefinitions)
+	)
+
+	err := fs.WalkDir(source.fsys, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
-			log.Printf("Failed to walk in locale path: %s", err.Error())
+			if path == "." && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
 			return err
 		}
 
-		if !info.IsDir() && filepath.Ext(path) == ".json" {
-			if _, err = t.bundle.LoadMessageFile(path); err != nil {
-				log.Printf("Failed to walk in locale path: %s", err.Error())
+		if d.IsDir() || !isMessageFile(path) {
+			return nil
+		}
+
+		name := filepath.Join(source.name, path)
+		buf, err := fs.ReadFile(source.fsys, path)
+		var file *i18n.MessageFile
+		if err == nil {
+			file, err = i18n.ParseMessageFileBytes(buf, name, unmarshalFuncs)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			return nil
+		}
+
+		if t.config.Namespaces {
+			namespaceMessages(file, path)
+		}
+
+		collisions := definitions.add(file)
+		if t.config.Namespaces {
+			errs = append(errs, collisions...)
+		} else {
+			for _, collision := range collisions {
+				t.logger().Warn("translation: message is overridden", "error", collision)
 			}
 		}
+		files = append(files, file)
 
 		return nil
 	})
+
+	return files, errors.Join(append(errs, err)...)
 }
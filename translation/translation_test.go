@@ -0,0 +1,382 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+// newTranslationStub writes the locale files to c.PathLocale, or to a
+// temporary directory when it is empty, and creates a translation with c.
+func newTranslationStub(tb testing.TB, c Config, files map[string]string) Translation {
+	tb.Helper()
+
+	if c.PathLocale == "" {
+		c.PathLocale = tb.TempDir()
+	}
+
+	for name, content := range files {
+		path := filepath.Join(c.PathLocale, name)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			tb.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+
+	return NewTranslation(c)
+}
+
+func TestNewTranslation_IndependentInstances(t *testing.T) {
+	first := newTranslationStub(t, Config{Locale: "en"}, map[string]string{"en.json": `{"greeting": "Hello"}`})
+	second := newTranslationStub(t, Config{Locale: "en"}, map[string]string{"en.json": `{"greeting": "Howdy"}`})
+
+	assert.Equal(t, "Hello", first.Trans("greeting", nil))
+	assert.Equal(t, "Howdy", second.Trans("greeting", nil))
+}
+
+func TestTrans_WithoutDefault(t *testing.T) {
+	SetDefault(nil)
+
+	assert.NotPanics(t, func() {
+		assert.Equal(t, "greeting", Trans("greeting", nil))
+	})
+}
+
+func TestTrans_WithDefault(t *testing.T) {
+	trans := newTranslationStub(t, Config{Locale: "en"}, map[string]string{"en.json": `{"greeting": "Hello"}`})
+
+	SetDefault(trans)
+	defer SetDefault(nil)
+
+	assert.Equal(t, trans, Default())
+	assert.Equal(t, "Hello", Trans("greeting", nil))
+}
+
+func TestTranslation_Reload(t *testing.T) {
+	dir := t.TempDir()
+	trans := newTranslationStub(t, Config{Locale: "en", PathLocale: dir}, map[string]string{"en.json": `{"greeting": "Hello"}`})
+
+	assert.Equal(t, "Hello", trans.Trans("greeting", nil))
+
+	if err := os.WriteFile(filepath.Join(dir, "en.json"), []byte(`{"greeting": "Hi"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, trans.Reload())
+	assert.Equal(t, "Hi", trans.Trans("greeting", nil))
+}
+
+func TestTranslation_ReloadKeepsBundleOnError(t *testing.T) {
+	dir := t.TempDir()
+	trans := newTranslationStub(t, Config{Locale: "en", PathLocale: dir}, map[string]string{"en.json": `{"greeting": "Hello"}`})
+
+	if err := os.WriteFile(filepath.Join(dir, "en.json"), []byte(`{"greeting": `), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Error(t, trans.Reload())
+	assert.Equal(t, "Hello", trans.Trans("greeting", nil))
+}
+
+func TestTranslation_Watch(t *testing.T) {
+	dir := t.TempDir()
+	trans := newTranslationStub(t, Config{Locale: "en", PathLocale: dir}, map[string]string{"en.json": `{"greeting": "Hello"}`})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go trans.Watch(ctx, 10*time.Millisecond)
+
+	if err := os.WriteFile(filepath.Join(dir, "fa.json"), []byte(`{"greeting": "سلام"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Eventually(t, func() bool {
+		return trans.Trans("greeting", nil, "fa") == "سلام"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestTranslation_WatchNonPositiveInterval(t *testing.T) {
+	trans := newTranslationStub(t, Config{Locale: "en"}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NotPanics(t, func() {
+		trans.Watch(ctx, 0)
+	})
+}
+
+func TestTranslation_WatchReportsReloadError(t *testing.T) {
+	dir := t.TempDir()
+	errs := make(chan error, 1)
+	trans := newTranslationStub(t, Config{
+		Locale:     "en",
+		PathLocale: dir,
+		OnReloadError: func(err error) {
+			errs <- err
+		},
+	}, map[string]string{"en.json": `{"greeting": "Hello"}`})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go trans.Watch(ctx, 10*time.Millisecond)
+
+	if err := os.WriteFile(filepath.Join(dir, "en.json"), []byte(`{"greeting": `), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-errs:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("reload error was not reported")
+	}
+	assert.Equal(t, "Hello", trans.Trans("greeting", nil))
+}
+
+// failingFS is an fs.FS that cannot be read.
+type failingFS struct{}
+
+func (failingFS) Open(string) (fs.File, error) {
+	return nil, fs.ErrPermission
+}
+
+func TestTranslation_WatchReportsErrorOnce(t *testing.T) {
+	var reported atomic.Int32
+	trans := NewTranslation(Config{
+		Locale: "en",
+		FS:     failingFS{},
+		Logger: NopLogger,
+		OnReloadError: func(err error) {
+			reported.Add(1)
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go trans.Watch(ctx, 5*time.Millisecond)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(1), reported.Load())
+}
+
+// flakyStore is a Store failing until it is fixed.
+type flakyStore struct {
+	fixed atomic.Bool
+}
+
+func (s *flakyStore) Messages(context.Context) (map[language.Tag][]*i18n.Message, error) {
+	if !s.fixed.Load() {
+		return nil, errors.New("store: connection refused")
+	}
+
+	return map[language.Tag][]*i18n.Message{
+		language.English: {{ID: "greeting", Other: "Hi"}},
+	}, nil
+}
+
+func TestTranslation_WatchRetriesStore(t *testing.T) {
+	store := &flakyStore{}
+	var reported atomic.Int32
+	trans := newTranslationStub(t, Config{
+		Locale: "en",
+		Store:  store,
+		Logger: NopLogger,
+		OnReloadError: func(err error) {
+			reported.Add(1)
+		},
+	}, map[string]string{"en.json": `{"greeting": "Hello"}`})
+	assert.Equal(t, "Hello", trans.Trans("greeting", nil))
+
+	// The store fails on load and until fixed; its failure is retried
+	// without any change of the message files.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go trans.Watch(ctx, 5*time.Millisecond)
+
+	time.Sleep(50 * time.Millisecond)
+	store.fixed.Store(true)
+
+	assert.Eventually(t, func() bool {
+		return trans.Trans("greeting", nil) == "Hi"
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(1), reported.Load())
+}
+
+func TestNewTranslation_CatalogFormats(t *testing.T) {
+	trans := newTranslationStub(t, Config{Locale: "en"}, map[string]string{
+		"en.yaml": "yaml:\n  greeting: Hello from YAML\n",
+		"de.yml":  "greeting: Hallo\n",
+		"fr.toml": "greeting = \"Bonjour\"\n",
+		"fa.po":   "msgid \"greeting\"\nmsgstr \"سلام\"\n",
+	})
+
+	assert.Equal(t, "Hello from YAML", trans.Trans("yaml.greeting", nil))
+	assert.Equal(t, "Hallo", trans.Trans("greeting", nil, "de"))
+	assert.Equal(t, "Bonjour", trans.Trans("greeting", nil, "fr"))
+	assert.Equal(t, "سلام", trans.Trans("greeting", nil, "fa"))
+}
+
+func TestNewTranslation_FS(t *testing.T) {
+	trans := NewTranslation(Config{
+		Locale: "en",
+		FS: fstest.MapFS{
+			"locales/en.json": {Data: []byte(`{"greeting": "Hello", "farewell": "Bye"}`)},
+		},
+	})
+
+	assert.Equal(t, "Hello", trans.Trans("greeting", nil))
+	assert.Equal(t, "Bye", trans.Trans("farewell", nil))
+}
+
+func TestNewTranslation_PathLocaleOverridesFS(t *testing.T) {
+	trans := newTranslationStub(t, Config{
+		Locale: "en",
+		FS: fstest.MapFS{
+			"en.json": {Data: []byte(`{"greeting": "Hello", "farewell": "Bye"}`)},
+		},
+	}, map[string]string{"en.json": `{"greeting": "Howdy"}`})
+
+	assert.Equal(t, "Howdy", trans.Trans("greeting", nil))
+	assert.Equal(t, "Bye", trans.Trans("farewell", nil))
+}
+
+func TestNewTranslation_MissingPathLocale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "locales")
+	trans := NewTranslation(Config{Locale: "en", PathLocale: path})
+
+	assert.Equal(t, "greeting", trans.Trans("greeting", nil))
+	assert.NoError(t, trans.Reload())
+	assert.NoDirExists(t, path)
+}
+
+func TestTranslation_TransPlural(t *testing.T) {
+	trans := newTranslationStub(t, Config{Locale: "en"}, map[string]string{
+		"en.json": `{"items": {"one": "{{.count}} item", "other": "{{.count}} items"}}`,
+		"ar.json": `{"items": {
+			"zero": "لا عناصر", "one": "عنصر واحد", "two": "عنصران",
+			"few": "{{.count}} عناصر", "many": "{{.count}} عنصرًا", "other": "{{.count}} عنصر"
+		}}`,
+	})
+
+	assert.Equal(t, "1 item", trans.TransPlural("items", 1, nil))
+	assert.Equal(t, "5 items", trans.TransPlural("items", 5, nil))
+	assert.Equal(t, "7 items", trans.TransPlural("items", 5, map[string]interface{}{"count": 7}))
+
+	tests := map[int]string{
+		0:   "لا عناصر",
+		1:   "عنصر واحد",
+		2:   "عنصران",
+		3:   "3 عناصر",
+		11:  "11 عنصرًا",
+		100: "100 عنصر",
+	}
+	for count, want := range tests {
+		assert.Equal(t, want, trans.TransPlural("items", count, nil, "ar"))
+	}
+}
+
+func TestTranslation_FallbackChain(t *testing.T) {
+	trans := newTranslationStub(t, Config{
+		Locale:         "fa-IR",
+		FallbackLocale: "de",
+		BundleLocale:   "en",
+	}, map[string]string{
+		"fa-IR.json": `{"regional": "منطقه‌ای"}`,
+		"fa.json":    `{"regional": "فارسی", "persian": "فارسی"}`,
+		"de.json":    `{"german": "Deutsch", "english": "Deutsch"}`,
+		"en.json":    `{"english": "English", "german": "English"}`,
+	})
+
+	assert.Equal(t, "منطقه‌ای", trans.Trans("regional", nil))
+	assert.Equal(t, "فارسی", trans.Trans("persian", nil))
+	assert.Equal(t, "Deutsch", trans.Trans("german", nil))
+	assert.Equal(t, "Deutsch", trans.Trans("english", nil))
+	assert.Equal(t, "English", trans.Trans("english", nil, "en-GB"))
+	assert.Equal(t, "missing", trans.Trans("missing", nil))
+}
+
+func TestTranslation_BundleLocale(t *testing.T) {
+	trans := newTranslationStub(t, Config{BundleLocale: "fa"}, map[string]string{
+		"fa.json": `{"greeting": "سلام"}`,
+		"en.json": `{"farewell": "Bye"}`,
+	})
+
+	assert.Equal(t, "سلام", trans.Trans("greeting", nil, "en"))
+	assert.Equal(t, "Bye", trans.Trans("farewell", nil, "en"))
+	assert.Equal(t, "fa", trans.Negotiate().String())
+}
+
+// benchmarkFiles are the locale files of the benchmarks.
+var benchmarkFiles = map[string]string{
+	"en.json": `{"greeting": "Hello {{.name}}", "farewell": "Bye"}`,
+	"fa.json": `{"greeting": "سلام {{.name}}"}`,
+}
+
+func BenchmarkTranslation_Trans(b *testing.B) {
+	trans := newTranslationStub(b, Config{Locale: "fa", FallbackLocale: "en", Logger: NopLogger}, benchmarkFiles)
+	args := map[string]interface{}{"name": "Gopher"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		trans.Trans("greeting", args)
+	}
+}
+
+func BenchmarkTranslation_TransFallback(b *testing.B) {
+	trans := newTranslationStub(b, Config{Locale: "fa", FallbackLocale: "en", Logger: NopLogger}, benchmarkFiles)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		trans.Trans("farewell", nil, "fa-IR")
+	}
+}
+
+func BenchmarkTranslation_TransParallel(b *testing.B) {
+	trans := newTranslationStub(b, Config{Locale: "fa", FallbackLocale: "en", Logger: NopLogger}, benchmarkFiles)
+	args := map[string]interface{}{"name": "Gopher"}
+	languages := []string{"fa", "en", "fa-IR", "en-GB"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			trans.Trans("greeting", args, languages[i%len(languages)])
+		}
+	})
+}
+
+func TestTranslation_ConcurrentTransAndReload(t *testing.T) {
+	dir := t.TempDir()
+	trans := newTranslationStub(t, Config{Locale: "en", PathLocale: dir, Logger: NopLogger}, map[string]string{
+		"en.json": `{"greeting": "Hello"}`,
+		"fa.json": `{"greeting": "سلام"}`,
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			assert.NoError(t, trans.Reload())
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		assert.Equal(t, "سلام", trans.Trans("greeting", nil, "fa-IR"))
+		assert.Equal(t, "Hello", trans.Trans("greeting", nil, "en"))
+	}
+	<-done
+}
@@ -0,0 +1,120 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultWatchInterval is the polling interval of Watch when none is given.
+const defaultWatchInterval = time.Second
+
+// Watch polls the locale path every interval and reloads the bundle when a
+// message file is added, removed or modified. It blocks until ctx is done, so
+// it is usually started in its own goroutine. A non-positive interval defaults
+// to a second. An error is reported to Config.OnReloadError once while it
+// lasts. A reload that failed on the message files is retried on the next
+// change, one that failed on Config.Store on the next tick.
+func (t *translation) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var (
+		failed   string                     // version of the message files that failed to reload
+		retry    = t.snapshot().storeFailed // whether the last load failed on Config.Store
+		reported string                     // last reported error
+	)
+
+	report := func(err error) {
+		if err.Error() != reported {
+			reported = err.Error()
+			t.reloadFailed(err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current, err := t.fingerprint()
+			if err != nil {
+				report(err)
+				continue
+			}
+
+			changed := current != t.snapshot().version && current != failed
+			if !changed && !retry {
+				if failed == "" {
+					reported = ""
+				}
+				continue
+			}
+
+			if err = t.Reload(); err != nil {
+				failed = current
+				retry = errors.As(err, &storeError{})
+				report(err)
+				continue
+			}
+
+			failed, retry, reported = "", false, ""
+			t.logger().Info("translation: message files reloaded")
+		}
+	}
+}
+
+// reloadFailed reports a reload error to the configured callback.
+func (t *translation) reloadFailed(err error) {
+	if t.config.OnReloadError != nil {
+		t.config.OnReloadError(err)
+		return
+	}
+
+	t.logger().Error("translation: failed to reload message files", "error", err)
+}
+
+// fingerprint summarizes the name, size and modification time of every message
+// file in the locale sources, so that changes can be detected between two polls.
+func (t *translation) fingerprint() (string, error) {
+	var entries []string
+
+	for _, source := range t.localeSources() {
+		err := fs.WalkDir(source.fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if path == "." && errors.Is(err, fs.ErrNotExist) {
+					return fs.SkipAll
+				}
+				return err
+			}
+
+			if d.IsDir() || !isMessageFile(path) {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			entries = append(entries, fmt.Sprintf("%s/%s:%d:%d", source.name, path, info.Size(), info.ModTime().UnixNano()))
+
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	sort.Strings(entries)
+
+	return strings.Join(entries, "\n"), nil
+}